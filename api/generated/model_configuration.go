@@ -28,6 +28,18 @@ type Configuration struct {
 	// URL of this app's public API. Inferred automatically from request.
 	AppPublicAPIURL string `json:"appPublicAPIURL,omitempty"`
 
+	// Secret contained in the webhook URLs the edge calls, calls without it are rejected. Generated automatically if not set, kept if omitted on update. Never returned.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+
+	// Base URL of the OpenBOS API proxy.
+	BaseURL string `json:"baseURL,omitempty"`
+
+	// URL of the OAuth 2.0 token endpoint.
+	TokenURL string `json:"tokenURL,omitempty"`
+
+	// OAuth 2.0 scope requested for the access token.
+	Scope string `json:"scope,omitempty"`
+
 	// Flag to enable or disable fetching from this API
 	Enable *bool `json:"enable,omitempty"`
 
@@ -37,6 +49,39 @@ type Configuration struct {
 	// Timeout in seconds
 	RequestTimeout *int32 `json:"requestTimeout,omitempty"`
 
+	// Number of retries for failed requests to the OpenBOS API. 0 disables retrying.
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// Delay in milliseconds before the first retry. Doubled with every further retry.
+	RetryBaseDelay *int32 `json:"retryBaseDelay,omitempty"`
+
+	// Upper bound in milliseconds for the delay between retries
+	RetryMaxDelay *int32 `json:"retryMaxDelay,omitempty"`
+
+	// What happens to Eliona assets whose asset or space was removed from OpenBOS. Archive tags them as archived and stops updating them, delete deletes them, stale tags them as stale until they reappear.
+	DeletionPolicy *string `json:"deletionPolicy,omitempty"`
+
+	// What happens to live data OpenBOS reports with bad or uncertain quality. Drop ignores it, forward writes the value to Eliona, hold keeps the last good value. Except for drop, the quality is shown in a quality attribute of the datapoint.
+	BadQualityPolicy *string `json:"badQualityPolicy,omitempty"`
+
+	// Replace timestamps that are ahead of the local clock by the local time. Such timestamps are caused by a clock skew of the edge.
+	ClampFutureTimestamps *bool `json:"clampFutureTimestamps,omitempty"`
+
+	// Units the edge should convert values to, as a map from the ID of the OpenBOS unit of a datapoint to the ID of the desired unit. The Eliona attributes get the desired unit.
+	UnitPreferences *map[string]string `json:"unitPreferences,omitempty"`
+
+	// Conversions done by the app for units the edge cannot convert. The Eliona attributes get the unit converted to.
+	UnitConversions *[]UnitConversion `json:"unitConversions,omitempty"`
+
+	// Prepended to the OpenBOS tags propagated to Eliona assets and alarm rules. Tags with the prefix are owned by the app and removed again when OpenBOS drops them.
+	TagPrefix *string `json:"tagPrefix,omitempty"`
+
+	// Shell-style patterns (`*`, `?`, `[...]`) of the OpenBOS tags propagated to Eliona. All tags are propagated if empty.
+	TagAllowList *[]string `json:"tagAllowList,omitempty"`
+
+	// Eliona icons for OpenBOS template icons, as a map from the OpenBOS icon identifier to the Eliona icon name. Overrides and extends the built-in mapping. Identifiers are stored in lower case without separators, and may not repeat an identifier in a different spelling.
+	IconMapping *map[string]string `json:"iconMapping,omitempty"`
+
 	// Array of rules combined by logical OR
 	AssetFilter [][]FilterRule `json:"assetFilter,omitempty"`
 
@@ -48,6 +93,12 @@ type Configuration struct {
 
 	// ID of the last Eliona user who created or updated the configuration
 	UserId *string `json:"userId,omitempty"`
+
+	TimestampStatistics *TimestampStatistics `json:"timestampStatistics,omitempty"`
+
+	DatapointCacheStatistics *DatapointCacheStatistics `json:"datapointCacheStatistics,omitempty"`
+
+	LiveDataQueueStatistics *LiveDataQueueStatistics `json:"liveDataQueueStatistics,omitempty"`
 }
 
 // AssertConfigurationRequired checks if the required fields are not zero-ed
@@ -55,6 +106,28 @@ func AssertConfigurationRequired(obj Configuration) error {
 	if err := AssertRecurseInterfaceRequired(obj.AssetFilter, AssertFilterRuleRequired); err != nil {
 		return err
 	}
+	if obj.UnitConversions != nil {
+		for _, el := range *obj.UnitConversions {
+			if err := AssertUnitConversionRequired(el); err != nil {
+				return err
+			}
+		}
+	}
+	if obj.TimestampStatistics != nil {
+		if err := AssertTimestampStatisticsRequired(*obj.TimestampStatistics); err != nil {
+			return err
+		}
+	}
+	if obj.DatapointCacheStatistics != nil {
+		if err := AssertDatapointCacheStatisticsRequired(*obj.DatapointCacheStatistics); err != nil {
+			return err
+		}
+	}
+	if obj.LiveDataQueueStatistics != nil {
+		if err := AssertLiveDataQueueStatisticsRequired(*obj.LiveDataQueueStatistics); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -63,5 +136,27 @@ func AssertConfigurationConstraints(obj Configuration) error {
 	if err := AssertRecurseInterfaceRequired(obj.AssetFilter, AssertFilterRuleConstraints); err != nil {
 		return err
 	}
+	if obj.UnitConversions != nil {
+		for _, el := range *obj.UnitConversions {
+			if err := AssertUnitConversionConstraints(el); err != nil {
+				return err
+			}
+		}
+	}
+	if obj.TimestampStatistics != nil {
+		if err := AssertTimestampStatisticsConstraints(*obj.TimestampStatistics); err != nil {
+			return err
+		}
+	}
+	if obj.DatapointCacheStatistics != nil {
+		if err := AssertDatapointCacheStatisticsConstraints(*obj.DatapointCacheStatistics); err != nil {
+			return err
+		}
+	}
+	if obj.LiveDataQueueStatistics != nil {
+		if err := AssertLiveDataQueueStatisticsConstraints(*obj.LiveDataQueueStatistics); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -18,10 +18,19 @@ package apiservices
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	apiserver "open-bos/api/generated"
 	appmodel "open-bos/app/model"
+	"open-bos/broker"
 	dbhelper "open-bos/db/helper"
+	"open-bos/eliona"
+	"slices"
+	"sync/atomic"
+
+	"github.com/eliona-smart-building-assistant/go-utils/common"
+	"github.com/eliona-smart-building-assistant/go-utils/log"
 )
 
 // ConfigurationAPIService is a service that implements the logic for the ConfigurationAPIServicer
@@ -30,6 +39,26 @@ import (
 type ConfigurationAPIService struct {
 }
 
+// LiveDataQueueStats describes the live data queue of a configuration.
+type LiveDataQueueStats struct {
+	Depth     int64
+	MaxDepth  int64
+	Processed int64
+	Failed    int64
+	Rejected  int64
+}
+
+// liveDataQueueStats returns the statistics of the live data queue of a
+// configuration, or false if it has none. The queue belongs to the webhook
+// listener, which registers it when it starts.
+var liveDataQueueStats atomic.Pointer[func(configID int64) (LiveDataQueueStats, bool)]
+
+// SetLiveDataQueueStats registers the source of the live data queue statistics
+// returned with the configurations.
+func SetLiveDataQueueStats(stats func(configID int64) (LiveDataQueueStats, bool)) {
+	liveDataQueueStats.Store(&stats)
+}
+
 // NewConfigurationAPIService creates a default api service
 func NewConfigurationAPIService() apiserver.ConfigurationAPIServicer {
 	return &ConfigurationAPIService{}
@@ -49,6 +78,22 @@ func (s *ConfigurationAPIService) GetConfigurations(ctx context.Context) (apiser
 
 func (s *ConfigurationAPIService) PostConfiguration(ctx context.Context, config apiserver.Configuration) (apiserver.ImplResponse, error) {
 	appConfig := toAppConfig(config)
+	if !appConfig.DeletionPolicy.IsValid() {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, fmt.Errorf("invalid deletion policy %q", appConfig.DeletionPolicy)
+	}
+	if !appConfig.BadQualityPolicy.IsValid() {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, fmt.Errorf("invalid bad quality policy %q", appConfig.BadQualityPolicy)
+	}
+	if err := appConfig.ValidateUnits(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	if err := appConfig.ValidateTagAllowList(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	if err := appConfig.ValidateIconMapping(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	appConfig.IconMapping = appmodel.NormalizeIconMapping(appConfig.IconMapping)
 	insertedConfig, err := dbhelper.InsertConfig(ctx, appConfig)
 	if err != nil {
 		return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
@@ -70,39 +115,184 @@ func (s *ConfigurationAPIService) GetConfigurationById(ctx context.Context, conf
 func (s *ConfigurationAPIService) PutConfigurationById(ctx context.Context, configId int64, config apiserver.Configuration) (apiserver.ImplResponse, error) {
 	config.Id = &configId
 	appConfig := toAppConfig(config)
+	if !appConfig.DeletionPolicy.IsValid() {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, fmt.Errorf("invalid deletion policy %q", appConfig.DeletionPolicy)
+	}
+	if !appConfig.BadQualityPolicy.IsValid() {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, fmt.Errorf("invalid bad quality policy %q", appConfig.BadQualityPolicy)
+	}
+	if err := appConfig.ValidateUnits(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	if err := appConfig.ValidateTagAllowList(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	if err := appConfig.ValidateIconMapping(); err != nil {
+		return apiserver.ImplResponse{Code: http.StatusBadRequest}, err
+	}
+	appConfig.IconMapping = appmodel.NormalizeIconMapping(appConfig.IconMapping)
+	existingConfig, err := dbhelper.GetConfig(ctx, configId)
+	if err != nil && !errors.Is(err, dbhelper.ErrNotFound) {
+		return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
+	}
+	if appConfig.WebhookSecret == "" {
+		// Keep the secret the edge is subscribed with. A new configuration gets
+		// one generated by the database.
+		appConfig.WebhookSecret = existingConfig.WebhookSecret
+	}
 	upsertedConfig, err := dbhelper.UpsertConfig(ctx, appConfig)
 	if err != nil {
 		return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
 	}
+	// Send all asset types again with the next synchronization, in case they
+	// were changed in Eliona meanwhile.
+	eliona.ForgetAssetTypes(configId)
+	if !maps.Equal(existingConfig.UnitPreferences, appConfig.UnitPreferences) || !slices.Equal(existingConfig.UnitConversions, appConfig.UnitConversions) ||
+		existingConfig.TagPrefix != appConfig.TagPrefix || !slices.Equal(existingConfig.TagAllowList, appConfig.TagAllowList) ||
+		!maps.Equal(existingConfig.IconMapping, appConfig.IconMapping) {
+		// The units and icons are part of the asset types and the tags part
+		// of the assets, which are only compared with the snapshot otherwise.
+		if err := dbhelper.DeleteOntologySnapshot(ctx, configId); err != nil {
+			return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
+		}
+	}
 	return apiserver.Response(http.StatusCreated, toAPIConfig(upsertedConfig)), nil
 }
 
 func (s *ConfigurationAPIService) DeleteConfigurationById(ctx context.Context, configId int64) (apiserver.ImplResponse, error) {
-	err := dbhelper.DeleteConfig(ctx, configId)
+	config, err := dbhelper.GetConfig(ctx, configId)
 	if errors.Is(err, dbhelper.ErrNotFound) {
 		return apiserver.ImplResponse{Code: http.StatusNotFound}, nil
 	}
 	if err != nil {
 		return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
 	}
+	// The edge would keep calling the webhooks of a deleted configuration.
+	// Failing to unsubscribe must not prevent the deletion though.
+	subscriptions, err := dbhelper.GetSubscriptions(ctx, configId)
+	if err != nil {
+		log.Error("dbhelper", "getting subscriptions of config %d: %v", configId, err)
+	}
+	if err := broker.Unsubscribe(ctx, config, subscriptions); err != nil {
+		log.Error("broker", "unsubscribing config %d: %v", configId, err)
+	}
+
+	err = dbhelper.DeleteConfig(ctx, configId)
+	if errors.Is(err, dbhelper.ErrNotFound) {
+		return apiserver.ImplResponse{Code: http.StatusNotFound}, nil
+	}
+	if err != nil {
+		return apiserver.ImplResponse{Code: http.StatusInternalServerError}, err
+	}
+	broker.ForgetClient(configId)
+	eliona.ForgetAssetTypes(configId)
 	return apiserver.ImplResponse{Code: http.StatusNoContent}, nil
 }
 
 func toAPIConfig(appConfig appmodel.Configuration) apiserver.Configuration {
 	return apiserver.Configuration{
-		Id:              &appConfig.Id,
-		Gwid:            appConfig.Gwid,
-		ClientID:        appConfig.ClientID,
-		ClientSecret:    appConfig.ClientSecret,
-		AppPublicAPIURL: appConfig.AppPublicAPIURL,
-		AssetFilter:     toAPIAssetFilter(appConfig.AssetFilter),
-		Enable:          &appConfig.Enable,
-		RefreshInterval: appConfig.RefreshInterval,
-		RequestTimeout:  &appConfig.RequestTimeout,
-		Active:          &appConfig.Active,
-		ProjectIDs:      &appConfig.ProjectIDs,
-		UserId:          &appConfig.UserId,
+		Id:                       &appConfig.Id,
+		Gwid:                     appConfig.Gwid,
+		ClientID:                 appConfig.ClientID,
+		ClientSecret:             appConfig.ClientSecret,
+		AppPublicAPIURL:          appConfig.AppPublicAPIURL,
+		BaseURL:                  appConfig.BaseURL,
+		TokenURL:                 appConfig.TokenURL,
+		Scope:                    appConfig.Scope,
+		AssetFilter:              toAPIAssetFilter(appConfig.AssetFilter),
+		Enable:                   &appConfig.Enable,
+		RefreshInterval:          appConfig.RefreshInterval,
+		RequestTimeout:           &appConfig.RequestTimeout,
+		MaxRetries:               &appConfig.MaxRetries,
+		RetryBaseDelay:           &appConfig.RetryBaseDelay,
+		RetryMaxDelay:            &appConfig.RetryMaxDelay,
+		DeletionPolicy:           common.Ptr(string(appConfig.DeletionPolicy)),
+		BadQualityPolicy:         common.Ptr(string(appConfig.BadQualityPolicy)),
+		ClampFutureTimestamps:    &appConfig.ClampFutureTimestamps,
+		UnitPreferences:          &appConfig.UnitPreferences,
+		UnitConversions:          toAPIUnitConversions(appConfig.UnitConversions),
+		TagPrefix:                &appConfig.TagPrefix,
+		TagAllowList:             &appConfig.TagAllowList,
+		IconMapping:              &appConfig.IconMapping,
+		TimestampStatistics:      toAPITimestampStatistics(broker.GetTimestampStatistics(appConfig.Id)),
+		DatapointCacheStatistics: toAPIDatapointCacheStatistics(dbhelper.ConfigDatapointCacheStats(appConfig.Id)),
+		LiveDataQueueStatistics:  toAPILiveDataQueueStatistics(appConfig.Id),
+		Active:                   &appConfig.Active,
+		ProjectIDs:               &appConfig.ProjectIDs,
+		UserId:                   &appConfig.UserId,
+	}
+}
+
+func toAPITimestampStatistics(stats broker.TimestampStatistics) *apiserver.TimestampStatistics {
+	apiStats := apiserver.TimestampStatistics{
+		Samples:        stats.Samples,
+		FutureSamples:  stats.FutureSamples,
+		ClampedSamples: stats.ClampedSamples,
+		MaxFutureSkew:  stats.MaxFutureSkew.Seconds(),
+	}
+	if !stats.LastFutureAt.IsZero() {
+		apiStats.LastFutureAt = &stats.LastFutureAt
+	}
+	return &apiStats
+}
+
+func toAPIDatapointCacheStatistics(stats dbhelper.CacheStats) *apiserver.DatapointCacheStatistics {
+	return &apiserver.DatapointCacheStatistics{
+		Entries: int64(stats.Entries),
+		Hits:    stats.Hits,
+		Misses:  stats.Misses,
+	}
+}
+
+// toAPILiveDataQueueStatistics returns nil if the configuration did not
+// receive live data since the app started.
+func toAPILiveDataQueueStatistics(configID int64) *apiserver.LiveDataQueueStatistics {
+	statsOf := liveDataQueueStats.Load()
+	if statsOf == nil {
+		return nil
+	}
+	stats, ok := (*statsOf)(configID)
+	if !ok {
+		return nil
+	}
+	return &apiserver.LiveDataQueueStatistics{
+		Depth:     stats.Depth,
+		MaxDepth:  stats.MaxDepth,
+		Processed: stats.Processed,
+		Failed:    stats.Failed,
+		Rejected:  stats.Rejected,
+	}
+}
+
+func toAPIUnitConversions(conversions []appmodel.UnitConversion) *[]apiserver.UnitConversion {
+	result := []apiserver.UnitConversion{}
+	for _, conversion := range conversions {
+		result = append(result, apiserver.UnitConversion{
+			From:   conversion.From,
+			To:     conversion.To,
+			Factor: common.Ptr(conversion.Factor),
+			Offset: common.Ptr(conversion.Offset),
+		})
+	}
+	return &result
+}
+
+func toAppUnitConversions(conversions []apiserver.UnitConversion) (result []appmodel.UnitConversion) {
+	for _, conversion := range conversions {
+		appConversion := appmodel.UnitConversion{
+			From:   conversion.From,
+			To:     conversion.To,
+			Factor: 1,
+		}
+		if conversion.Factor != nil {
+			appConversion.Factor = *conversion.Factor
+		}
+		if conversion.Offset != nil {
+			appConversion.Offset = *conversion.Offset
+		}
+		result = append(result, appConversion)
 	}
+	return result
 }
 
 func toAPIAssetFilter(appAF [][]appmodel.FilterRule) (result [][]apiserver.FilterRule) {
@@ -124,6 +314,19 @@ func toAppConfig(apiConfig apiserver.Configuration) (appConfig appmodel.Configur
 	appConfig.ClientID = apiConfig.ClientID
 	appConfig.ClientSecret = apiConfig.ClientSecret
 	appConfig.AppPublicAPIURL = apiConfig.AppPublicAPIURL
+	appConfig.WebhookSecret = apiConfig.WebhookSecret
+	appConfig.BaseURL = apiConfig.BaseURL
+	if appConfig.BaseURL == "" {
+		appConfig.BaseURL = broker.DefaultBaseURL
+	}
+	appConfig.TokenURL = apiConfig.TokenURL
+	if appConfig.TokenURL == "" {
+		appConfig.TokenURL = broker.DefaultTokenURL
+	}
+	appConfig.Scope = apiConfig.Scope
+	if appConfig.Scope == "" {
+		appConfig.Scope = broker.DefaultScope
+	}
 
 	if apiConfig.Id != nil {
 		appConfig.Id = *apiConfig.Id
@@ -132,6 +335,44 @@ func toAppConfig(apiConfig apiserver.Configuration) (appConfig appmodel.Configur
 	if apiConfig.RequestTimeout != nil {
 		appConfig.RequestTimeout = *apiConfig.RequestTimeout
 	}
+	appConfig.MaxRetries = broker.DefaultMaxRetries
+	if apiConfig.MaxRetries != nil {
+		appConfig.MaxRetries = *apiConfig.MaxRetries
+	}
+	appConfig.RetryBaseDelay = broker.DefaultRetryBaseDelay
+	if apiConfig.RetryBaseDelay != nil {
+		appConfig.RetryBaseDelay = *apiConfig.RetryBaseDelay
+	}
+	appConfig.RetryMaxDelay = broker.DefaultRetryMaxDelay
+	if apiConfig.RetryMaxDelay != nil {
+		appConfig.RetryMaxDelay = *apiConfig.RetryMaxDelay
+	}
+	appConfig.DeletionPolicy = appmodel.DeletionPolicyArchive
+	if apiConfig.DeletionPolicy != nil {
+		appConfig.DeletionPolicy = appmodel.DeletionPolicy(*apiConfig.DeletionPolicy)
+	}
+	appConfig.BadQualityPolicy = appmodel.BadQualityPolicyHold
+	if apiConfig.BadQualityPolicy != nil {
+		appConfig.BadQualityPolicy = appmodel.BadQualityPolicy(*apiConfig.BadQualityPolicy)
+	}
+	if apiConfig.ClampFutureTimestamps != nil {
+		appConfig.ClampFutureTimestamps = *apiConfig.ClampFutureTimestamps
+	}
+	if apiConfig.UnitPreferences != nil {
+		appConfig.UnitPreferences = *apiConfig.UnitPreferences
+	}
+	if apiConfig.UnitConversions != nil {
+		appConfig.UnitConversions = toAppUnitConversions(*apiConfig.UnitConversions)
+	}
+	if apiConfig.TagPrefix != nil {
+		appConfig.TagPrefix = *apiConfig.TagPrefix
+	}
+	if apiConfig.TagAllowList != nil {
+		appConfig.TagAllowList = *apiConfig.TagAllowList
+	}
+	if apiConfig.IconMapping != nil {
+		appConfig.IconMapping = *apiConfig.IconMapping
+	}
 	if apiConfig.AssetFilter != nil {
 		appConfig.AssetFilter = toAppAssetFilter(apiConfig.AssetFilter)
 	}
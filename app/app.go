@@ -33,7 +33,6 @@ import (
 
 	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
 	"github.com/eliona-smart-building-assistant/go-eliona/app"
-	"github.com/eliona-smart-building-assistant/go-eliona/asset"
 	"github.com/eliona-smart-building-assistant/go-eliona/frontend"
 	"github.com/eliona-smart-building-assistant/go-utils/common"
 	"github.com/eliona-smart-building-assistant/go-utils/db"
@@ -71,9 +70,7 @@ func CollectData() {
 
 	for _, config := range configs {
 		if !config.Enable {
-			if config.Active {
-				dbhelper.SetConfigActiveState(context.Background(), config, false)
-			}
+			deactivateConfig(config)
 			continue
 		}
 
@@ -93,27 +90,34 @@ func CollectData() {
 
 		common.RunOnceWithParam(func(config appmodel.Configuration) {
 			log.Info("main", "Collecting %d started.", config.Id)
-			if err := collectResources(&config); err != nil {
+			ctx := contextForConfig(config.Id)
+			if err := collectResources(ctx, &config); err != nil {
 				return // Error is handled in the method itself.
 			}
 			log.Info("main", "Collecting %d finished.", config.Id)
 
-			if err := broker.SubscribeToOntologyChanges(config); err != nil {
+			subscription, err := broker.SubscribeToOntologyChanges(ctx, config)
+			if err != nil {
 				log.Error("broker", "subscribing to ontology changes: %v", err)
 				return
 			}
+			storeSubscription(ctx, config, subscription)
 			log.Info("main", "Subscribed to ontology updates of config %d", config.Id)
 
-			if err := broker.SubscribeToDataChanges(config); err != nil {
+			subscription, err = broker.SubscribeToDataChanges(ctx, config)
+			if err != nil {
 				log.Error("broker", "subscribing to data changes: %v", err)
 				return
 			}
+			storeSubscription(ctx, config, subscription)
 			log.Info("main", "Subscribed to data updates of config %d", config.Id)
 
-			if err := broker.SubscribeToAlarms(config); err != nil {
+			subscription, err = broker.SubscribeToAlarms(ctx, config)
+			if err != nil {
 				log.Error("broker", "subscribing to alarm changes: %v", err)
 				return
 			}
+			storeSubscription(ctx, config, subscription)
 			log.Info("main", "Subscribed to alarm updates of config %d", config.Id)
 
 			time.Sleep(time.Hour * time.Duration(config.RefreshInterval))
@@ -121,6 +125,28 @@ func CollectData() {
 	}
 }
 
+// deactivateConfig stops all work for a disabled configuration and removes its
+// webhook subscriptions from the edge, so that it stops sending events.
+func deactivateConfig(config appmodel.Configuration) {
+	cancelConfigContext(config.Id)
+	eliona.ForgetAssetTypes(config.Id)
+	if !config.Active {
+		return
+	}
+	subscriptions, err := dbhelper.GetSubscriptions(context.Background(), config.Id)
+	if err != nil {
+		log.Error("dbhelper", "getting subscriptions of config %d: %v", config.Id, err)
+	}
+	if err := broker.Unsubscribe(baseCtx, config, subscriptions); err != nil {
+		log.Error("broker", "unsubscribing config %d: %v", config.Id, err)
+	} else if err := dbhelper.DeleteSubscriptions(context.Background(), config.Id); err != nil {
+		log.Error("dbhelper", "deleting subscriptions of config %d: %v", config.Id, err)
+	} else {
+		log.Info("main", "Unsubscribed config %d from OpenBOS updates", config.Id)
+	}
+	dbhelper.SetConfigActiveState(context.Background(), config, false)
+}
+
 func CollectConfigData(configID int64) {
 	config, err := dbhelper.GetConfig(context.Background(), configID)
 	if err != nil {
@@ -129,9 +155,7 @@ func CollectConfigData(configID int64) {
 	}
 
 	if !config.Enable {
-		if config.Active {
-			dbhelper.SetConfigActiveState(context.Background(), config, false)
-		}
+		deactivateConfig(config)
 		return
 	}
 	if !config.Active {
@@ -139,14 +163,19 @@ func CollectConfigData(configID int64) {
 	}
 
 	log.Info("main", "Collecting %d triggered by update.", config.Id)
-	if err := collectResources(&config); err != nil {
+	if err := collectResources(contextForConfig(config.Id), &config); err != nil {
 		return // Error is handled in the method itself.
 	}
 	log.Info("main", "Collecting %d finished.", config.Id)
 }
 
-func collectResources(config *appmodel.Configuration) error {
-	version, assetTypes, root, err := broker.FetchOntology(*config)
+func collectResources(ctx context.Context, config *appmodel.Configuration) (err error) {
+	snapshot, err := dbhelper.GetOntologySnapshot(ctx, config.Id)
+	if err != nil && !errors.Is(err, dbhelper.ErrNotFound) {
+		log.Error("dbhelper", "getting ontology snapshot: %v", err)
+		return err
+	}
+	update, err := broker.FetchOntology(ctx, *config, snapshot)
 	if errors.Is(err, broker.ErrNoUpdate) {
 		log.Debug("broker", "ontology is up-to-date")
 		return nil
@@ -155,18 +184,39 @@ func collectResources(config *appmodel.Configuration) error {
 		log.Error("broker", "fetching assets: %v", err)
 		return err
 	}
-	for _, assetType := range assetTypes {
-		if err := asset.InitAssetType(assetType)(nil); err != nil {
+	if update.Diff.Full {
+		log.Info("broker", "synchronizing complete ontology version %d of config %d", update.Version, config.Id)
+	} else {
+		log.Info("broker", "synchronizing ontology version %d of config %d: %v", update.Version, config.Id, update.Diff)
+	}
+	// Even a partially applied update changes the mapping.
+	defer dbhelper.InvalidateDatapointCache(config.Id)
+	defer func() {
+		if err != nil {
+			// Eliona may not have the asset types the app assumes it has.
+			eliona.ForgetAssetTypes(config.Id)
+		}
+	}()
+	for _, assetType := range update.AssetTypes {
+		if err := eliona.InitAssetType(config.Id, assetType); err != nil {
 			log.Error("eliona", "initializing asset type: %v", err)
 			return err
 		}
 	}
-	if err := eliona.CreateAssets(*config, root); err != nil {
-		log.Error("eliona", "creating assets: %v", err)
+	if err := eliona.ApplyOntologyDiff(*config, update.Root, update.Diff); err != nil {
+		log.Error("eliona", "applying ontology changes: %v", err)
+		return err
+	}
+	if err := eliona.ReconcileRemoved(*config, update.KnownIDs); err != nil {
+		log.Error("eliona", "reconciling removed assets: %v", err)
+		return err
+	}
+	if err := dbhelper.SaveOntologySnapshot(ctx, config.Id, update.Version, update.Snapshot); err != nil {
+		log.Error("dbhelper", "saving ontology snapshot: %v", err)
 		return err
 	}
 
-	config.OntologyVersion = version
+	config.OntologyVersion = update.Version
 	dbhelper.UpdateConfigOntologyVersion(context.Background(), *config)
 
 	return nil
@@ -176,74 +226,147 @@ type AttributeDataUpdate struct {
 	ConfigID            int64
 	DatapointProviderID string
 	Timestamp           time.Time
+	ReceivedAt          time.Time // When the webhook received the update.
+	Quality             broker.Quality
+	UnitSymbol          string // Unit the edge sent the value in, if reported.
 	Value               any
 }
 
-func UpdateDataPointInEliona(update AttributeDataUpdate) {
-	config, err := dbhelper.GetConfig(context.Background(), update.ConfigID)
-	if err != nil {
-		log.Error("dbhelper", "Couldn't read config %d from DB: %v", update.ConfigID, err)
-		return
+// DatapointAssetKey returns a key that is the same for all datapoints of an
+// asset, so that their live data can be processed together. Only the cache is
+// consulted, so that receiving live data never waits for the database.
+// Datapoints not cached yet get a key of their own.
+func DatapointAssetKey(configID int64, datapointID string) string {
+	datapoint, ok := dbhelper.PeekDatapointById(datapointID, configID)
+	if !ok {
+		return "datapoint:" + datapointID
 	}
-	if !config.Enable {
-		if config.Active {
-			dbhelper.SetConfigActiveState(context.Background(), config, false)
+	return fmt.Sprintf("asset:%d", datapoint.Asset.ID)
+}
+
+// UpdateDataPointsInEliona writes a batch of live data to Eliona. Values of
+// the same asset, subtype and timestamp end up in one data record, and all
+// records are sent in a single request. Values of a quality other than good
+// are handled according to the bad quality policy of the configuration.
+// Returns the number of updates that could not be written.
+func UpdateDataPointsInEliona(updates []AttributeDataUpdate) (failed int) {
+	configs := make(map[int64]*appmodel.Configuration) // Nil if the config is not usable.
+	var records []eliona.AssetData
+	var qualityKeys []qualityKey
+	pending := 0 // Updates that are lost if writing the records fails.
+	for _, update := range updates {
+		config, ok := configs[update.ConfigID]
+		if !ok {
+			config = enabledConfig(update.ConfigID)
+			configs[update.ConfigID] = config
 		}
-		return
-	}
-	if !config.Active {
-		dbhelper.SetConfigActiveState(context.Background(), config, true)
-	}
+		if config == nil {
+			continue
+		}
+		update.Timestamp = broker.CheckTimestamp(config.Id, update.Timestamp, update.ReceivedAt, config.ClampFutureTimestamps)
 
-	assetData := make(map[string]any)
-	datapoint, err := dbhelper.GetDatapointById(update.DatapointProviderID, config.Id)
-	if errors.Is(err, dbhelper.ErrNotFound) {
-		log.Info("dbhelper", "datapoint not found (this may be caused by asset filter): %v", err)
-		return
-	}
-	if err != nil {
-		log.Error("dbhelper", "getting datapoint by ID %v for config %v: %v", update.DatapointProviderID, config.Id, err)
-		return
-	}
-	// Complex decode support
-	if complexData, ok := update.Value.(map[string]any); ok {
-		decodedData := complexdata.DecodeComplexData(complexData, datapoint.AttributeNamePrefix)
-		for k, v := range decodedData {
-			assetData[k] = v
+		if update.Quality != broker.QualityGood && config.BadQualityPolicy == appmodel.BadQualityPolicyDrop {
+			log.Debug("app", "Dropping data of ID %s with quality %s", update.DatapointProviderID, update.Quality)
+			continue
 		}
-	} else {
-		// If not complex, find the attribute name and map directly
-		if len(datapoint.Attributes) != 1 {
-			log.Error("inconsistency", "received non-complex data %+v, but found datapoint providerID %v with %v != 1 attributes", update, datapoint.ProviderID, len(datapoint.Attributes))
-			return
+
+		datapoint, err := dbhelper.GetDatapointById(update.DatapointProviderID, config.Id)
+		if errors.Is(err, dbhelper.ErrNotFound) {
+			log.Info("dbhelper", "datapoint not found (this may be caused by asset filter): %v", err)
+			continue
+		}
+		if err != nil {
+			log.Error("dbhelper", "getting datapoint by ID %v for config %v: %v", update.DatapointProviderID, config.Id, err)
+			failed++
+			continue
 		}
-		assetData[datapoint.Attributes[0].Name] = update.Value
+
+		if config.BadQualityPolicy != appmodel.BadQualityPolicyDrop {
+			if record, key, changed := qualityRecord(config.Id, datapoint, update.Quality.AttributeValue(), update.Timestamp); changed {
+				records = append(records, record)
+				qualityKeys = append(qualityKeys, key)
+			}
+		}
+		if update.Quality != broker.QualityGood && config.BadQualityPolicy == appmodel.BadQualityPolicyHold {
+			log.Debug("app", "Holding last good value of ID %s, received quality %s", update.DatapointProviderID, update.Quality)
+			pending++
+			continue
+		}
+
+		assetData := make(map[string]any)
+		// Complex decode support
+		if complexData, ok := update.Value.(map[string]any); ok {
+			decodedData := complexdata.DecodeComplexData(complexData, datapoint.AttributeNamePrefix)
+			for k, v := range decodedData {
+				assetData[k] = v
+			}
+		} else {
+			// If not complex, find the attribute name and map directly
+			if len(datapoint.Attributes) != 1 {
+				log.Error("inconsistency", "received non-complex data %+v, but found datapoint providerID %v with %v != 1 attributes", update, datapoint.ProviderID, len(datapoint.Attributes))
+				failed++
+				continue
+			}
+			assetData[datapoint.Attributes[0].Name] = update.Value
+		}
+		// Units are converted first, so that values are rounded to their
+		// format only once converted.
+		convertToEliona(*config, datapoint, assetData, update.UnitSymbol)
+		broker.CoerceToEliona(datapoint.Attributes, assetData)
+		records = append(records, eliona.AssetData{
+			AssetID:   datapoint.Asset.AssetID,
+			Subtype:   api.DataSubtype(datapoint.Subtype),
+			Timestamp: update.Timestamp,
+			Data:      assetData,
+		})
+		pending++
 	}
 
-	if err := eliona.UpsertAssetData(datapoint.Asset.AssetID, assetData, update.Timestamp, api.DataSubtype(datapoint.Subtype)); err != nil {
+	if err := eliona.UpsertAssetDataBulk(records); err != nil {
 		log.Error("eliona", "upserting data: %v", err)
-		return
+		forgetQualities(qualityKeys)
+		return failed + pending
+	}
+	return failed
+}
+
+// enabledConfig returns the configuration if it is enabled, marking it active.
+// Returns nil otherwise.
+func enabledConfig(configID int64) *appmodel.Configuration {
+	config, err := dbhelper.GetConfig(context.Background(), configID)
+	if err != nil {
+		log.Error("dbhelper", "Couldn't read config %d from DB: %v", configID, err)
+		return nil
+	}
+	if !config.Enable {
+		deactivateConfig(config)
+		return nil
 	}
+	if !config.Active {
+		dbhelper.SetConfigActiveState(context.Background(), config, true)
+	}
+	return &config
 }
 
 type AlarmUpdate struct {
 	ConfigID            int64
 	DatapointInstanceId string
 	Timestamp           time.Time
+	ReceivedAt          time.Time // When the webhook received the update.
 	AlarmID             string
 	Name                string
 	Description         string
-	Trigger             string
+	Trigger             broker.Trigger
 	Active              bool
 	Acked               bool
 	Closed              bool
 	TimeStamp           string
-	Quality             string
+	Quality             broker.Quality
 	Value               any
 	AckedBy             string
 	Comment             string
 	NeedAcknowledge     bool
-	Severity            string
+	Severity            broker.Severity
 	AssetId             string
 	SpaceId             string
 	AssetName           string
@@ -255,13 +378,13 @@ type AlarmUpdate struct {
 
 func (alarm AlarmUpdate) getPriority() int {
 	switch alarm.Severity {
-	case "Critical", "Urgent":
+	case broker.SeverityCritical, broker.SeverityUrgent:
 		return 1 // High priority
-	case "High":
+	case broker.SeverityHigh:
 		return 2 // Medium priority
-	case "Low":
+	case broker.SeverityLow:
 		return 3 // Low priority
-	case "Log":
+	case broker.SeverityLog:
 		return 10 // Info
 	default:
 		return 10 // Default to lowest priority if severity is unknown
@@ -307,6 +430,23 @@ func (alarm AlarmUpdate) getAckMessage() string {
 	return fmt.Sprintf("%s: %s", alarm.AckedBy, alarm.Comment)
 }
 
+// alarmQualityHandling returns whether an alarm updates the quality attribute
+// of its datapoint and the alarm in Eliona, according to the bad quality
+// policy. The quality is not set for every alarm, such alarms only update the
+// alarm.
+func alarmQualityHandling(quality broker.Quality, policy appmodel.BadQualityPolicy) (updateQuality bool, updateAlarm bool) {
+	switch {
+	case quality == broker.QualityUnknown:
+		return false, true
+	case policy == appmodel.BadQualityPolicyDrop:
+		return false, quality == broker.QualityGood
+	case policy == appmodel.BadQualityPolicyHold:
+		return true, quality == broker.QualityGood
+	default:
+		return true, true
+	}
+}
+
 func UpdateAlarmInEliona(update AlarmUpdate) {
 	config, err := dbhelper.GetConfig(context.Background(), update.ConfigID)
 	if err != nil {
@@ -314,14 +454,18 @@ func UpdateAlarmInEliona(update AlarmUpdate) {
 		return
 	}
 	if !config.Enable {
-		if config.Active {
-			dbhelper.SetConfigActiveState(context.Background(), config, false)
-		}
+		deactivateConfig(config)
 		return
 	}
 	if !config.Active {
 		dbhelper.SetConfigActiveState(context.Background(), config, true)
 	}
+	update.Timestamp = broker.CheckTimestamp(config.Id, update.Timestamp, update.ReceivedAt, config.ClampFutureTimestamps)
+	updateQuality, updateAlarm := alarmQualityHandling(update.Quality, config.BadQualityPolicy)
+	if !updateQuality && !updateAlarm {
+		log.Debug("app", "Dropping alarm with quality %s for SessionId %s", update.Quality, update.AlarmID)
+		return
+	}
 	datapoint, err := dbhelper.GetDatapointById(update.DatapointInstanceId, config.Id)
 	if errors.Is(err, dbhelper.ErrNotFound) {
 		log.Info("dbhelper", "datapoint not found (this may be caused by asset filter): %v", err)
@@ -332,9 +476,23 @@ func UpdateAlarmInEliona(update AlarmUpdate) {
 		return
 	}
 
+	if updateQuality {
+		if record, key, changed := qualityRecord(config.Id, datapoint, update.Quality.AttributeValue(), update.Timestamp); changed {
+			if err := eliona.UpsertAssetDataBulk([]eliona.AssetData{record}); err != nil {
+				log.Error("eliona", "upserting quality of datapoint %s: %v", datapoint.ProviderID, err)
+				forgetQualities([]qualityKey{key})
+			}
+		}
+	}
+	if !updateAlarm {
+		log.Debug("app", "Holding state of alarm for SessionId %s, received quality %s", update.AlarmID, update.Quality)
+		return
+	}
+
 	// Alarm rule creation. This might be eventually moved to ontology sync.
+	tags := config.ElionaTags(datapoint.Tags, update.Tags)
 	for i := range datapoint.Attributes {
-		elionaAlarmID, err := eliona.CreateAlarm(datapoint.Asset.AssetID, datapoint.Subtype, datapoint.Attributes[i].Name, update.NeedAcknowledge, update.getPriority(), update.buildAlarmMessage())
+		elionaAlarmID, err := eliona.CreateAlarm(datapoint.Asset.AssetID, datapoint.Subtype, datapoint.Attributes[i].Name, update.NeedAcknowledge, update.getPriority(), update.buildAlarmMessage(), tags)
 		if err != nil {
 			log.Error("eliona", "creating alarm: %v", err)
 			return
@@ -358,6 +516,41 @@ func UpdateAlarmInEliona(update AlarmUpdate) {
 	}
 }
 
+var lastCacheStats dbhelper.CacheStats
+
+// LogCacheStatistics logs the hit and miss counts of the datapoint cache if
+// they changed since the last call.
+func LogCacheStatistics() {
+	stats := dbhelper.DatapointCacheStats()
+	if stats == lastCacheStats {
+		return
+	}
+	lastCacheStats = stats
+	log.Info("dbhelper", "Datapoint cache: %v", stats)
+}
+
+// loggedUnknownValues holds the count of each unknown value when it was last
+// logged, keyed without the count.
+var loggedUnknownValues = make(map[broker.UnknownValue]int64)
+
+// LogUnknownValues logs the values received from OpenBOS that could not be
+// parsed, if they are new or were received again since the last call.
+func LogUnknownValues() {
+	for _, value := range broker.UnknownValues() {
+		key := value
+		key.Count = 0
+		if loggedUnknownValues[key] == value.Count {
+			continue
+		}
+		loggedUnknownValues[key] = value.Count
+		if value.Others {
+			log.Warn("broker", "Received other unknown %s values from OpenBOS %d times", value.Enum, value.Count)
+		} else {
+			log.Warn("broker", "Received unknown %s %q from OpenBOS %d times", value.Enum, value.Value, value.Count)
+		}
+	}
+}
+
 // ListenForOutputChanges listens to output attribute changes from Eliona.
 func ListenForOutputChanges() {
 	for { // We want to restart listening in case something breaks.
@@ -396,7 +589,11 @@ func outputData(assetID int32, data map[string]interface{}) error {
 
 		var latestData any
 		if len(datapoint.Attributes) == 1 {
-			latestData = value
+			attribute := datapoint.Attributes[0]
+			latestData, err = broker.CoerceToOpenBOS(attribute, convertToOpenBOS(datapoint.Asset.Config, attribute, value))
+			if err != nil {
+				return fmt.Errorf("coercing value of attribute %v: %v", attribute.Name, err)
+			}
 		} else {
 			// Fetch and format the latest data for all attributes of the datapoint
 			latestData, err = formatComplexData(datapoint)
@@ -415,7 +612,8 @@ func outputData(assetID int32, data map[string]interface{}) error {
 		return nil
 	}
 
-	if err := broker.PutData(attributesData[0].Datapoint.Asset.Config, attributesData); err != nil {
+	config := attributesData[0].Datapoint.Asset.Config
+	if err := broker.PutData(contextForConfig(config.Id), config, attributesData); err != nil {
 		return fmt.Errorf("putting data: %v", err)
 	}
 
@@ -440,7 +638,10 @@ func formatComplexData(datapoint appmodel.Datapoint) (interface{}, error) {
 		if len(pathParts) < 2 {
 			return nil, fmt.Errorf("inconsistency: not a nested attribute")
 		}
-		complexData[pathParts[1]] = value
+		complexData[pathParts[1]], err = broker.CoerceToOpenBOS(attr, convertToOpenBOS(datapoint.Asset.Config, attr, value))
+		if err != nil {
+			return nil, fmt.Errorf("coercing value of attribute %v: %v", attr.Name, err)
+		}
 	}
 
 	return complexData, nil
@@ -478,7 +679,7 @@ func ListenForAlarmChanges() {
 				log.Error("eliona", "getting ack username: %v", err)
 				username = ""
 			}
-			if err := broker.AcknowledgeAlarm(config, alarm.OpenBOSAlarmID, username, output.GetAcknowledgeText()); err != nil {
+			if err := broker.AcknowledgeAlarm(contextForConfig(config.Id), config, alarm.OpenBOSAlarmID, username, output.GetAcknowledgeText()); err != nil {
 				log.Error("broker", "acknowledging alarm: %v", err)
 			}
 		}
@@ -0,0 +1,68 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// baseCtx is the parent of all configuration contexts. It gets cancelled once
+// the app shuts down.
+var baseCtx = context.Background()
+
+// SetBaseContext sets the context all OpenBOS calls are derived from. Must be
+// called before any of the app services are started.
+func SetBaseContext(ctx context.Context) {
+	baseCtx = ctx
+}
+
+type configContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// configContexts holds one context per enabled configuration, so that
+// disabling a configuration aborts the OpenBOS calls still in flight for it.
+var configContexts = struct {
+	sync.Mutex
+	m map[int64]configContext
+}{m: make(map[int64]configContext)}
+
+// contextForConfig returns the context for calls made on behalf of the
+// configuration.
+func contextForConfig(configID int64) context.Context {
+	configContexts.Lock()
+	defer configContexts.Unlock()
+
+	if cc, ok := configContexts.m[configID]; ok && cc.ctx.Err() == nil {
+		return cc.ctx
+	}
+	ctx, cancel := context.WithCancel(baseCtx)
+	configContexts.m[configID] = configContext{ctx: ctx, cancel: cancel}
+	return ctx
+}
+
+// cancelConfigContext aborts all calls in flight for the configuration.
+func cancelConfigContext(configID int64) {
+	configContexts.Lock()
+	defer configContexts.Unlock()
+
+	if cc, ok := configContexts.m[configID]; ok {
+		cc.cancel()
+		delete(configContexts.m, configID)
+	}
+}
@@ -15,20 +15,299 @@
 
 package appmodel
 
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
 type Configuration struct {
-	Id              int64
-	Gwid            string
-	ClientID        string
-	ClientSecret    string
-	OntologyVersion int32
-	AppPublicAPIURL string
-	RefreshInterval int32
-	RequestTimeout  int32
-	AssetFilter     [][]FilterRule
-	Enable          bool
-	Active          bool
-	ProjectIDs      []string
-	UserId          string
+	Id                    int64
+	Gwid                  string
+	ClientID              string
+	ClientSecret          string
+	OntologyVersion       int32
+	AppPublicAPIURL       string
+	WebhookSecret         string // Part of the webhook URLs, authenticates calls from the edge.
+	BaseURL               string
+	TokenURL              string
+	Scope                 string
+	RefreshInterval       int32
+	RequestTimeout        int32
+	MaxRetries            int32
+	RetryBaseDelay        int32 // Milliseconds
+	RetryMaxDelay         int32 // Milliseconds
+	DeletionPolicy        DeletionPolicy
+	BadQualityPolicy      BadQualityPolicy
+	ClampFutureTimestamps bool              // Replace timestamps ahead of the local clock by the local time.
+	UnitPreferences       map[string]string // OpenBOS unit ID to the ID of the unit the edge should send instead.
+	UnitConversions       []UnitConversion  // Conversions the app does where the edge cannot convert.
+	TagPrefix             string            // Prepended to OpenBOS tags in Eliona.
+	TagAllowList          []string          // Patterns of OpenBOS tags propagated to Eliona, empty for all.
+	IconMapping           map[string]string // OpenBOS icon to Eliona icon, overriding the built-in mapping.
+	AssetFilter           [][]FilterRule
+	Enable                bool
+	Active                bool
+	ProjectIDs            []string
+	UserId                string
+}
+
+// DeletionPolicy defines what happens to Eliona assets whose OpenBOS asset or
+// space was removed from the ontology.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyArchive tags the Eliona asset as archived and forgets the
+	// mapping, so that the asset no longer receives any data.
+	DeletionPolicyArchive DeletionPolicy = "archive"
+	// DeletionPolicyDelete deletes the Eliona asset together with its mapping.
+	DeletionPolicyDelete DeletionPolicy = "delete"
+	// DeletionPolicyStale tags the Eliona asset as stale but keeps the mapping,
+	// so that the asset is picked up again if it reappears in OpenBOS.
+	DeletionPolicyStale DeletionPolicy = "stale"
+)
+
+func (p DeletionPolicy) IsValid() bool {
+	switch p {
+	case DeletionPolicyArchive, DeletionPolicyDelete, DeletionPolicyStale:
+		return true
+	}
+	return false
+}
+
+// BadQualityPolicy defines what happens to live data OpenBOS reports with a
+// quality other than good.
+type BadQualityPolicy string
+
+const (
+	// BadQualityPolicyDrop ignores the data, as if it had never been sent.
+	BadQualityPolicyDrop BadQualityPolicy = "drop"
+	// BadQualityPolicyForward writes the value to Eliona and marks the quality
+	// of the datapoint.
+	BadQualityPolicyForward BadQualityPolicy = "forward"
+	// BadQualityPolicyHold keeps the last good value in Eliona and marks the
+	// quality of the datapoint.
+	BadQualityPolicyHold BadQualityPolicy = "hold"
+)
+
+func (p BadQualityPolicy) IsValid() bool {
+	switch p {
+	case BadQualityPolicyDrop, BadQualityPolicyForward, BadQualityPolicyHold:
+		return true
+	}
+	return false
+}
+
+// UnitConversion converts values of an OpenBOS unit to another unit by
+// to = from * Factor + Offset.
+type UnitConversion struct {
+	From   string // OpenBOS unit ID
+	To     string // OpenBOS unit ID
+	Factor float64
+	Offset float64
+}
+
+// UnitConversion returns the conversion of values of the OpenBOS unit.
+func (c Configuration) UnitConversion(unitID string) (UnitConversion, bool) {
+	if unitID == "" {
+		return UnitConversion{}, false
+	}
+	for _, conversion := range c.UnitConversions {
+		if conversion.From == unitID {
+			return conversion, true
+		}
+	}
+	return UnitConversion{}, false
+}
+
+// ValidateUnits checks that each unit is converted at most once, either by
+// the edge or by the app, and that conversions can be reverted.
+func (c Configuration) ValidateUnits() error {
+	converted := make(map[string]bool)
+	for _, conversion := range c.UnitConversions {
+		if conversion.From == "" || conversion.To == "" {
+			return fmt.Errorf("unit conversion %+v lacks a unit", conversion)
+		}
+		if conversion.Factor == 0 {
+			return fmt.Errorf("unit conversion from %s has factor 0", conversion.From)
+		}
+		if converted[conversion.From] {
+			return fmt.Errorf("unit %s is converted more than once", conversion.From)
+		}
+		if _, ok := c.UnitPreferences[conversion.From]; ok {
+			return fmt.Errorf("unit %s has both a preference and a conversion", conversion.From)
+		}
+		converted[conversion.From] = true
+	}
+	return nil
+}
+
+func (c UnitConversion) Convert(value float64) float64 {
+	return value*c.Factor + c.Offset
+}
+
+// Revert converts a value back to the OpenBOS unit.
+func (c UnitConversion) Revert(value float64) float64 {
+	return (value - c.Offset) / c.Factor
+}
+
+// ValidateTagAllowList checks that the patterns of the tag allow-list are
+// well-formed.
+func (c Configuration) ValidateTagAllowList() error {
+	for _, pattern := range c.TagAllowList {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("tag allow-list pattern '%s': %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ElionaTags maps OpenBOS tags to Eliona tags. Tags not matching any pattern
+// of the allow-list are dropped, the others get the tag prefix. The result is
+// sorted and free of duplicates.
+func (c Configuration) ElionaTags(tagLists ...[]string) []string {
+	var tags []string
+	for _, tagList := range tagLists {
+		for _, tag := range tagList {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || !c.tagAllowed(tag) {
+				continue
+			}
+			tags = append(tags, c.TagPrefix+tag)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+func (c Configuration) tagAllowed(tag string) bool {
+	if len(c.TagAllowList) == 0 {
+		return true
+	}
+	for _, pattern := range c.TagAllowList {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ElionaIcons are the icons Eliona offers for asset types.
+var ElionaIcons = []string{
+	"blind", "building", "button", "closable", "elevator", "environment", "fallback", "filling", "gateway", "light", "mailbox",
+	"parking", "people", "power", "rack", "storey", "trash", "ventilation", "vibration", "water", "weather",
+}
+
+// ValidateIconMapping checks that OpenBOS icons are mapped to icons Eliona
+// offers, and that no OpenBOS icon is mapped twice. Icons are compared ignoring
+// case and separators, see NormalizeIdentifier.
+func (c Configuration) ValidateIconMapping() error {
+	normalized := make(map[string]string)
+	for _, openBOSIcon := range slices.Sorted(maps.Keys(c.IconMapping)) {
+		elionaIcon := c.IconMapping[openBOSIcon]
+		if !slices.Contains(ElionaIcons, elionaIcon) {
+			return fmt.Errorf("icon %s is mapped to %q, which is none of the Eliona icons %v", openBOSIcon, elionaIcon, ElionaIcons)
+		}
+		key := NormalizeIdentifier(openBOSIcon)
+		if key == "" {
+			return fmt.Errorf("icon %q contains no letters or digits", openBOSIcon)
+		}
+		if other, ok := normalized[key]; ok {
+			return fmt.Errorf("icons %s and %s are the same icon", other, openBOSIcon)
+		}
+		normalized[key] = openBOSIcon
+	}
+	return nil
+}
+
+// NormalizeIconMapping returns the icon mapping keyed by the normalized OpenBOS
+// icons, see NormalizeIdentifier. If several icons are the same once
+// normalized, the first one in alphabetical order wins.
+func NormalizeIconMapping(iconMapping map[string]string) map[string]string {
+	normalized := make(map[string]string, len(iconMapping))
+	for _, openBOSIcon := range slices.Sorted(maps.Keys(iconMapping)) {
+		key := NormalizeIdentifier(openBOSIcon)
+		if _, ok := normalized[key]; !ok && key != "" {
+			normalized[key] = iconMapping[openBOSIcon]
+		}
+	}
+	return normalized
+}
+
+// NormalizeIdentifier lowercases an OpenBOS identifier and removes everything
+// but letters and digits, e.g. "Command_And_Feedback" becomes
+// "commandandfeedback".
+func NormalizeIdentifier(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, value)
+}
+
+// Values of the quality attribute of a datapoint.
+const (
+	QualityGood      = 0
+	QualityUncertain = 1
+	QualityBad       = 2
+)
+
+// QualityAttributeName returns the name of the attribute showing the quality
+// of the values of a datapoint.
+func QualityAttributeName(datapointName string) string {
+	return datapointName + "_quality"
+}
+
+type SubscriptionKind string
+
+const (
+	SubscriptionKindOntology SubscriptionKind = "ontology"
+	SubscriptionKindData     SubscriptionKind = "data"
+	SubscriptionKindAlarm    SubscriptionKind = "alarm"
+)
+
+var SubscriptionKinds = []SubscriptionKind{SubscriptionKindOntology, SubscriptionKindData, SubscriptionKindAlarm}
+
+// Subscription is a webhook subscription on the edge.
+type Subscription struct {
+	Kind           SubscriptionKind
+	SubscriptionID string // Empty if the edge did not return any.
+	WebhookURL     string
+	LeaseTime      time.Duration // Zero if the subscription never expires.
+	MaxSendTime    time.Duration // Interval of heartbeats, zero if the edge sends none.
+	SubscribedAt   time.Time     // Kept when the lease is renewed.
+	RenewedAt      time.Time
+	LastEventAt    time.Time // Zero if no event was received yet.
+}
+
+// LeaseExpiry returns when the edge drops the subscription unless it is
+// renewed, or the zero time if it never expires.
+func (s Subscription) LeaseExpiry() time.Time {
+	if s.LeaseTime <= 0 {
+		return time.Time{}
+	}
+	return s.RenewedAt.Add(s.LeaseTime)
+}
+
+// HeartbeatDeadline returns the time by which an event must have arrived
+// for the subscription to be considered alive, or the zero time if the edge
+// sends no heartbeats. A few heartbeats are allowed to get lost on the way.
+// Renewing the lease does not prove the edge still sends events, so the
+// deadline only depends on when the app subscribed and the last event.
+func (s Subscription) HeartbeatDeadline(missedHeartbeats int) time.Time {
+	if s.MaxSendTime <= 0 {
+		return time.Time{}
+	}
+	last := s.SubscribedAt
+	if s.LastEventAt.After(last) {
+		last = s.LastEventAt
+	}
+	return last.Add(time.Duration(missedHeartbeats+1) * s.MaxSendTime)
 }
 
 type FilterRule struct {
@@ -43,6 +322,7 @@ type Asset struct {
 	GlobalAssetID string
 	ProviderID    string
 	AssetID       int32
+	Stale         bool
 }
 
 type Datapoint struct {
@@ -51,16 +331,59 @@ type Datapoint struct {
 	Asset               *Asset
 	AttributeNamePrefix string
 	Attributes          []Attribute
+	Tags                []string // OpenBOS tags of the datapoint template.
 
 	Data map[string]any // For passing data of properties during ontology sync.
 }
 
 type Attribute struct {
-	ID   int64
-	Name string
+	ID         int64
+	Name       string
+	UnitID     string // OpenBOS unit the edge sends the values in, empty if there is none.
+	UnitSymbol string
+	Format     string // Format of the values, e.g. boolean or float. Empty if unknown.
 }
 
 type Alarm struct {
 	ElionaAlarmID  int32
 	OpenBOSAlarmID string
 }
+
+// ChangeSet classifies ontology entities of one kind by their OpenBOS ID. An
+// entity can be both changed and moved.
+type ChangeSet struct {
+	Added   []string
+	Changed []string
+	Moved   []string
+	Removed []string
+}
+
+func (c ChangeSet) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Moved) == 0 && len(c.Removed) == 0
+}
+
+func (c ChangeSet) String() string {
+	return fmt.Sprintf("+%d ~%d >%d -%d", len(c.Added), len(c.Changed), len(c.Moved), len(c.Removed))
+}
+
+// OntologyDiff describes what changed between the last synchronized ontology
+// and the current one.
+type OntologyDiff struct {
+	// Full is set if there was no previous ontology to compare with. All
+	// entities are then reported as added.
+	Full bool
+
+	Templates  ChangeSet
+	Assets     ChangeSet
+	Spaces     ChangeSet
+	Datapoints ChangeSet
+	Properties ChangeSet
+}
+
+func (d OntologyDiff) IsEmpty() bool {
+	return !d.Full && d.Templates.IsEmpty() && d.Assets.IsEmpty() && d.Spaces.IsEmpty() && d.Datapoints.IsEmpty() && d.Properties.IsEmpty()
+}
+
+func (d OntologyDiff) String() string {
+	return fmt.Sprintf("templates %s, assets %s, spaces %s, datapoints %s, properties %s", d.Templates, d.Assets, d.Spaces, d.Datapoints, d.Properties)
+}
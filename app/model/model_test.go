@@ -0,0 +1,24 @@
+package appmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIconMapping(t *testing.T) {
+	assert.NoError(t, Configuration{IconMapping: map[string]string{"Chiller": "environment", "evse": "power"}}.ValidateIconMapping())
+	assert.Error(t, Configuration{IconMapping: map[string]string{"chiller": "snowflake"}}.ValidateIconMapping(), "unknown Eliona icon")
+	assert.Error(t, Configuration{IconMapping: map[string]string{"heat_pump": "water", "HeatPump": "ventilation"}}.ValidateIconMapping(), "same icon mapped twice")
+	assert.Error(t, Configuration{IconMapping: map[string]string{"--": "water"}}.ValidateIconMapping(), "no icon left after normalizing")
+}
+
+func TestNormalizeIconMapping(t *testing.T) {
+	assert.Equal(t, map[string]string{"chiller": "environment", "heatpump": "ventilation"}, NormalizeIconMapping(map[string]string{
+		"Chiller":   "environment",
+		"heat_pump": "water",
+		"HeatPump":  "ventilation",
+		"--":        "water",
+	}))
+	assert.Empty(t, NormalizeIconMapping(nil))
+}
@@ -0,0 +1,75 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"sync"
+	"time"
+
+	appmodel "open-bos/app/model"
+	"open-bos/eliona"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+)
+
+type qualityKey struct {
+	configID   int64
+	providerID string
+}
+
+// lastQualities holds the quality last written to Eliona per datapoint, so
+// that the quality attribute is only written when it changes.
+var lastQualities = struct {
+	sync.Mutex
+	byDatapoint map[qualityKey]int
+}{byDatapoint: make(map[qualityKey]int)}
+
+// qualityChanged stores the quality of a datapoint and reports whether it
+// differs from the one stored before.
+func qualityChanged(key qualityKey, quality int) bool {
+	lastQualities.Lock()
+	defer lastQualities.Unlock()
+	last, ok := lastQualities.byDatapoint[key]
+	lastQualities.byDatapoint[key] = quality
+	return !ok || last != quality
+}
+
+// forgetQualities makes the quality of the datapoints be written again with
+// the next update, e.g. because writing it failed.
+func forgetQualities(keys []qualityKey) {
+	lastQualities.Lock()
+	defer lastQualities.Unlock()
+	for _, key := range keys {
+		delete(lastQualities.byDatapoint, key)
+	}
+}
+
+// qualityRecord returns the data record marking the quality of a datapoint or
+// property, or false if it did not change since the last one.
+func qualityRecord(configID int64, datapoint appmodel.Datapoint, quality int, timestamp time.Time) (eliona.AssetData, qualityKey, bool) {
+	key := qualityKey{configID: configID, providerID: datapoint.ProviderID}
+	if !qualityChanged(key, quality) {
+		return eliona.AssetData{}, key, false
+	}
+	return eliona.AssetData{
+		AssetID:   datapoint.Asset.AssetID,
+		Subtype:   api.SUBTYPE_STATUS,
+		Timestamp: timestamp,
+		Data: map[string]any{
+			appmodel.QualityAttributeName(datapoint.AttributeNamePrefix): quality,
+		},
+	}, key, true
+}
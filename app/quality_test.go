@@ -0,0 +1,56 @@
+package app
+
+import (
+	appmodel "open-bos/app/model"
+	"open-bos/broker"
+	"testing"
+	"time"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQualityRecordOfPropertyLiveData(t *testing.T) {
+	// Properties are stored with the status subtype, like the broker maps them.
+	property := appmodel.Datapoint{
+		ProviderID:          "property-1",
+		Subtype:             string(api.SUBTYPE_STATUS),
+		Asset:               &appmodel.Asset{AssetID: 7},
+		AttributeNamePrefix: "Serial number",
+	}
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	record, key, changed := qualityRecord(-1, property, appmodel.QualityBad, timestamp)
+	t.Cleanup(func() { forgetQualities([]qualityKey{key}) })
+	assert.True(t, changed)
+	assert.Equal(t, int32(7), record.AssetID)
+	assert.Equal(t, map[string]any{appmodel.QualityAttributeName("Serial number"): appmodel.QualityBad}, record.Data)
+
+	_, _, changed = qualityRecord(-1, property, appmodel.QualityBad, timestamp)
+	assert.False(t, changed, "unchanged qualities are not written again")
+}
+
+func TestAlarmQualityHandling(t *testing.T) {
+	tests := []struct {
+		quality       broker.Quality
+		policy        appmodel.BadQualityPolicy
+		updateQuality bool
+		updateAlarm   bool
+	}{
+		{broker.QualityGood, appmodel.BadQualityPolicyForward, true, true},
+		{broker.QualityBad, appmodel.BadQualityPolicyForward, true, true},
+		{broker.QualityGood, appmodel.BadQualityPolicyHold, true, true},
+		{broker.QualityUncertain, appmodel.BadQualityPolicyHold, true, false},
+		{broker.QualityGood, appmodel.BadQualityPolicyDrop, false, true},
+		{broker.QualityBad, appmodel.BadQualityPolicyDrop, false, false},
+		// Alarms without a quality are never dropped or held.
+		{broker.QualityUnknown, appmodel.BadQualityPolicyDrop, false, true},
+		{broker.QualityUnknown, appmodel.BadQualityPolicyHold, false, true},
+		{broker.QualityUnknown, appmodel.BadQualityPolicyForward, false, true},
+	}
+	for _, tt := range tests {
+		updateQuality, updateAlarm := alarmQualityHandling(tt.quality, tt.policy)
+		assert.Equal(t, tt.updateQuality, updateQuality, "quality attribute for %s with %s", tt.quality, tt.policy)
+		assert.Equal(t, tt.updateAlarm, updateAlarm, "alarm for %s with %s", tt.quality, tt.policy)
+	}
+}
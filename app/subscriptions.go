@@ -0,0 +1,168 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	"context"
+	appmodel "open-bos/app/model"
+	"open-bos/broker"
+	dbhelper "open-bos/db/helper"
+	"sync"
+	"time"
+
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+const (
+	// leaseRenewalMargin is how long before its lease expires a subscription
+	// gets renewed.
+	leaseRenewalMargin = time.Minute
+	// missedHeartbeats is how many heartbeats may get lost before a
+	// subscription is considered dead.
+	missedHeartbeats = 1
+)
+
+// Replaceable for tests.
+var (
+	renewSubscription    = broker.RenewSubscription
+	recreateSubscription = resubscribe
+	saveSubscription     = storeSubscription
+)
+
+// storeSubscription saves the subscription, replacing the stored one of the
+// same kind. If the webhook URL changed, e.g. because the webhook secret was
+// changed, the edge would keep calling the old URL, so the replaced
+// subscription is removed from the edge.
+func storeSubscription(ctx context.Context, config appmodel.Configuration, subscription appmodel.Subscription) {
+	previous, err := dbhelper.GetSubscriptions(context.Background(), config.Id)
+	if err != nil {
+		log.Error("dbhelper", "getting subscriptions of config %d: %v", config.Id, err)
+	}
+	for _, old := range previous {
+		if old.Kind != subscription.Kind || old.WebhookURL == subscription.WebhookURL {
+			continue
+		}
+		if err := broker.RemoveSubscription(ctx, config, old); err != nil {
+			log.Error("broker", "removing replaced %s subscription of config %d: %v", old.Kind, config.Id, err)
+		} else {
+			log.Info("main", "Removed replaced %s subscription of config %d", old.Kind, config.Id)
+		}
+	}
+	if err := dbhelper.SaveSubscription(context.Background(), config.Id, subscription); err != nil {
+		log.Error("dbhelper", "saving %s subscription of config %d: %v", subscription.Kind, config.Id, err)
+	}
+}
+
+type subscriptionKey struct {
+	configID int64
+	kind     appmodel.SubscriptionKind
+}
+
+// lastEvents holds when the edge last sent an event for a subscription. Every
+// webhook call is an event, so the times are only written to the database by
+// MaintainSubscriptions.
+var lastEvents = struct {
+	sync.Mutex
+	m map[subscriptionKey]time.Time
+}{m: make(map[subscriptionKey]time.Time)}
+
+// RecordSubscriptionEvent notes that the edge sent an event, heartbeats
+// included, for the subscription.
+func RecordSubscriptionEvent(configID int64, kind appmodel.SubscriptionKind) {
+	lastEvents.Lock()
+	defer lastEvents.Unlock()
+	lastEvents.m[subscriptionKey{configID: configID, kind: kind}] = time.Now()
+}
+
+// flushSubscriptionEvents writes the times of the last events to the database.
+// Times that could not be written are kept for the next attempt.
+func flushSubscriptionEvents() {
+	lastEvents.Lock()
+	events := lastEvents.m
+	lastEvents.m = make(map[subscriptionKey]time.Time)
+	lastEvents.Unlock()
+
+	for key, at := range events {
+		if err := dbhelper.TouchSubscription(context.Background(), key.configID, key.kind, at); err != nil {
+			log.Error("dbhelper", "recording %s event of config %d: %v", key.kind, key.configID, err)
+			lastEvents.Lock()
+			if newer, ok := lastEvents.m[key]; !ok || newer.Before(at) {
+				lastEvents.m[key] = at
+			}
+			lastEvents.Unlock()
+		}
+	}
+}
+
+// MaintainSubscriptions keeps the webhook subscriptions of all active
+// configurations alive. Leases are renewed before they expire, and
+// subscriptions the edge stopped sending heartbeats for are recreated.
+func MaintainSubscriptions() {
+	flushSubscriptionEvents()
+	configs, err := dbhelper.GetConfigs(context.Background())
+	if err != nil {
+		log.Error("dbhelper", "Couldn't read configs from DB: %v", err)
+		return
+	}
+	for _, config := range configs {
+		if !config.Enable || !config.Active {
+			continue
+		}
+		subscriptions, err := dbhelper.GetSubscriptions(context.Background(), config.Id)
+		if err != nil {
+			log.Error("dbhelper", "getting subscriptions of config %d: %v", config.Id, err)
+			continue
+		}
+		for _, subscription := range subscriptions {
+			maintainSubscription(contextForConfig(config.Id), config, subscription, time.Now())
+		}
+	}
+}
+
+func maintainSubscription(ctx context.Context, config appmodel.Configuration, subscription appmodel.Subscription, now time.Time) {
+	var renewed appmodel.Subscription
+	var err error
+	if deadline := subscription.HeartbeatDeadline(missedHeartbeats); !deadline.IsZero() && now.After(deadline) {
+		log.Warn("main", "No events for %s subscription of config %d since %v, subscribing again", subscription.Kind, config.Id, deadline)
+		renewed, err = recreateSubscription(ctx, config, subscription.Kind)
+	} else if expiry := subscription.LeaseExpiry(); !expiry.IsZero() && now.After(expiry.Add(-leaseRenewalMargin)) {
+		log.Debug("main", "Renewing lease of %s subscription of config %d", subscription.Kind, config.Id)
+		renewed, err = renewSubscription(ctx, config, subscription.Kind)
+		// The heartbeats are still expected since the app subscribed.
+		renewed.SubscribedAt = subscription.SubscribedAt
+	} else {
+		return
+	}
+	if err != nil {
+		log.Error("broker", "renewing %s subscription of config %d: %v", subscription.Kind, config.Id, err)
+		return
+	}
+	saveSubscription(ctx, config, renewed)
+}
+
+// resubscribe recreates a subscription the edge has lost, including everything
+// that was missed in the meantime.
+func resubscribe(ctx context.Context, config appmodel.Configuration, kind appmodel.SubscriptionKind) (appmodel.Subscription, error) {
+	switch kind {
+	case appmodel.SubscriptionKindOntology:
+		return broker.SubscribeToOntologyChanges(ctx, config)
+	case appmodel.SubscriptionKindData:
+		return broker.SubscribeToDataChanges(ctx, config)
+	case appmodel.SubscriptionKindAlarm:
+		return broker.SubscribeToAlarms(ctx, config)
+	}
+	return broker.RenewSubscription(ctx, config, kind)
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"errors"
+	appmodel "open-bos/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type subscriptionCalls struct {
+	renewed    []appmodel.SubscriptionKind
+	recreated  []appmodel.SubscriptionKind
+	saved      []appmodel.Subscription
+	renewError error
+}
+
+func stubSubscriptions(t *testing.T, now time.Time) *subscriptionCalls {
+	calls := &subscriptionCalls{}
+	previousRenew, previousRecreate, previousSave := renewSubscription, recreateSubscription, saveSubscription
+	t.Cleanup(func() {
+		renewSubscription, recreateSubscription, saveSubscription = previousRenew, previousRecreate, previousSave
+	})
+	renewSubscription = func(ctx context.Context, config appmodel.Configuration, kind appmodel.SubscriptionKind) (appmodel.Subscription, error) {
+		calls.renewed = append(calls.renewed, kind)
+		if calls.renewError != nil {
+			return appmodel.Subscription{}, calls.renewError
+		}
+		return appmodel.Subscription{Kind: kind, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute, SubscribedAt: now, RenewedAt: now}, nil
+	}
+	recreateSubscription = func(ctx context.Context, config appmodel.Configuration, kind appmodel.SubscriptionKind) (appmodel.Subscription, error) {
+		calls.recreated = append(calls.recreated, kind)
+		return appmodel.Subscription{Kind: kind, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute, SubscribedAt: now, RenewedAt: now}, nil
+	}
+	saveSubscription = func(ctx context.Context, config appmodel.Configuration, subscription appmodel.Subscription) {
+		calls.saved = append(calls.saved, subscription)
+	}
+	return calls
+}
+
+func TestMaintainSubscription(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	subscribed := now.Add(-time.Hour)
+	tests := []struct {
+		name          string
+		subscription  appmodel.Subscription
+		wantRenewed   bool
+		wantRecreated bool
+	}{
+		{
+			name: "healthy",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindData, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+				SubscribedAt: subscribed, RenewedAt: now.Add(-time.Minute), LastEventAt: now.Add(-time.Minute)},
+		},
+		{
+			name: "lease about to expire",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindData, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+				SubscribedAt: subscribed, RenewedAt: now.Add(-4*time.Minute - time.Second), LastEventAt: now.Add(-time.Minute)},
+			wantRenewed: true,
+		},
+		{
+			name: "heartbeats missed although the lease was renewed",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindData, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+				SubscribedAt: subscribed, RenewedAt: now.Add(-time.Minute), LastEventAt: now.Add(-5 * time.Minute)},
+			wantRecreated: true,
+		},
+		{
+			name: "no event since subscribing",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindAlarm, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+				SubscribedAt: subscribed, RenewedAt: now.Add(-time.Minute)},
+			wantRecreated: true,
+		},
+		{
+			name: "heartbeat deadline not reached yet",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindAlarm, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+				SubscribedAt: now.Add(-3 * time.Minute), RenewedAt: now.Add(-3 * time.Minute)},
+		},
+		{
+			name:         "neither lease nor heartbeats",
+			subscription: appmodel.Subscription{Kind: appmodel.SubscriptionKindOntology, SubscribedAt: subscribed, RenewedAt: subscribed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := stubSubscriptions(t, now)
+			maintainSubscription(context.Background(), appmodel.Configuration{Id: 1}, tt.subscription, now)
+
+			assert.Equal(t, tt.wantRenewed, len(calls.renewed) == 1, "renewed")
+			assert.Equal(t, tt.wantRecreated, len(calls.recreated) == 1, "recreated")
+			if tt.wantRenewed || tt.wantRecreated {
+				assert.Len(t, calls.saved, 1)
+			} else {
+				assert.Empty(t, calls.saved)
+			}
+		})
+	}
+}
+
+func TestMaintainSubscriptionKeepsSubscriptionTimeOnRenewal(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	subscribed := now.Add(-time.Hour)
+	calls := stubSubscriptions(t, now)
+
+	maintainSubscription(context.Background(), appmodel.Configuration{Id: 1}, appmodel.Subscription{
+		Kind: appmodel.SubscriptionKindData, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+		SubscribedAt: subscribed, RenewedAt: now.Add(-5 * time.Minute), LastEventAt: now.Add(-time.Minute),
+	}, now)
+
+	assert.Len(t, calls.saved, 1)
+	assert.Equal(t, subscribed, calls.saved[0].SubscribedAt)
+	assert.Equal(t, now, calls.saved[0].RenewedAt)
+
+	// Renewing the lease does not postpone detecting that heartbeats stopped.
+	deadline := calls.saved[0].HeartbeatDeadline(missedHeartbeats)
+	assert.Equal(t, subscribed.Add(4*time.Minute), deadline)
+}
+
+func TestMaintainSubscriptionDoesNotSaveFailedRenewal(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	calls := stubSubscriptions(t, now)
+	calls.renewError = errors.New("edge unreachable")
+
+	maintainSubscription(context.Background(), appmodel.Configuration{Id: 1}, appmodel.Subscription{
+		Kind: appmodel.SubscriptionKindData, LeaseTime: 5 * time.Minute, MaxSendTime: 2 * time.Minute,
+		SubscribedAt: now.Add(-time.Hour), RenewedAt: now.Add(-5 * time.Minute), LastEventAt: now.Add(-time.Minute),
+	}, now)
+
+	assert.Len(t, calls.renewed, 1)
+	assert.Empty(t, calls.saved)
+}
@@ -0,0 +1,82 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package app
+
+import (
+	appmodel "open-bos/app/model"
+	"strconv"
+	"strings"
+
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+// convertToEliona converts the values of the datapoint's attributes in data
+// for which the configuration has a unit conversion. unitSymbol is the unit the
+// edge reported for the values. If it differs from the unit of the attribute,
+// the edge converted the values already.
+func convertToEliona(config appmodel.Configuration, datapoint appmodel.Datapoint, data map[string]any, unitSymbol string) {
+	for _, attribute := range datapoint.Attributes {
+		conversion, ok := config.UnitConversion(attribute.UnitID)
+		if !ok || unitSymbol != "" && unitSymbol != attribute.UnitSymbol {
+			continue
+		}
+		value, ok := data[attribute.Name]
+		if !ok {
+			continue
+		}
+		number, ok := toFloat(value)
+		if !ok {
+			log.Warn("app", "cannot convert value %v of attribute %s from unit %s", value, attribute.Name, attribute.UnitID)
+			continue
+		}
+		data[attribute.Name] = conversion.Convert(number)
+	}
+}
+
+// convertToOpenBOS converts a value of the attribute back to the unit of the
+// datapoint in OpenBOS.
+func convertToOpenBOS(config appmodel.Configuration, attribute appmodel.Attribute, value any) any {
+	conversion, ok := config.UnitConversion(attribute.UnitID)
+	if !ok {
+		return value
+	}
+	number, ok := toFloat(value)
+	if !ok {
+		log.Warn("app", "cannot convert value %v of attribute %s to unit %s", value, attribute.Name, attribute.UnitID)
+		return value
+	}
+	return conversion.Revert(number)
+}
+
+// toFloat returns the value as a number. Numbers sent as text are parsed.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
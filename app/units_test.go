@@ -0,0 +1,38 @@
+package app
+
+import (
+	appmodel "open-bos/app/model"
+	"open-bos/broker"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToElionaBeforeCoercing(t *testing.T) {
+	config := appmodel.Configuration{UnitConversions: []appmodel.UnitConversion{
+		{From: "degF", To: "degC", Factor: 0.5, Offset: 0},
+	}}
+	datapoint := appmodel.Datapoint{Attributes: []appmodel.Attribute{
+		{Name: "count", UnitID: "degF", Format: string(broker.FormatInteger)},
+		{Name: "temperature", UnitID: "degF", Format: string(broker.FormatFloat)},
+	}}
+	data := map[string]any{"count": 3, "temperature": " 21.5"}
+
+	convertToEliona(config, datapoint, data, "")
+	broker.CoerceToEliona(datapoint.Attributes, data)
+
+	assert.Equal(t, int64(2), data["count"], "1.5 is rounded once converted")
+	assert.Equal(t, 10.75, data["temperature"], "numbers sent as text are converted")
+}
+
+func TestToFloat(t *testing.T) {
+	for value, expected := range map[any]float64{3: 3, int64(4): 4, float32(1.5): 1.5, "2.25": 2.25} {
+		number, ok := toFloat(value)
+		assert.True(t, ok, "%v", value)
+		assert.Equal(t, expected, number)
+	}
+	_, ok := toFloat("warm")
+	assert.False(t, ok)
+	_, ok = toFloat(true)
+	assert.False(t, ok)
+}
@@ -16,12 +16,15 @@
 package broker
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	appmodel "open-bos/app/model"
-	"open-bos/complexdata"
 	"open-bos/eliona"
-	"strings"
+	"slices"
+	"time"
 
 	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
 	"github.com/eliona-smart-building-assistant/go-utils/log"
@@ -31,18 +34,7 @@ const masterPropertyAttribute = "is_master"
 
 var ErrNoUpdate = errors.New("no new version available")
 
-// [datapoint-attribution]
-var datapointBelongingToAssetTemplate = make(map[string]datapointTemplatePreprocessedInfo) // map[datapointTemplateID]datapoints
-type datapointTemplatePreprocessedInfo struct {
-	name       string // datapoint name always
-	subtype    string
-	attributes []attributeTemplateInfo
-}
-type attributeTemplateInfo struct {
-	name string // datatype (name or id) . uncomplexified path
-}
-
-func convertAssetTemplateToAssetType(template assetTemplate) api.AssetType {
+func convertAssetTemplateToAssetType(template assetTemplate, iconMapping map[string]string) api.AssetType {
 	translatedName := "OpenBOS " + template.Name
 	apiAsset := api.AssetType{
 		Name: "open_bos_" + template.ID,
@@ -51,12 +43,15 @@ func convertAssetTemplateToAssetType(template assetTemplate) api.AssetType {
 		}),
 		Attributes: []api.AssetTypeAttribute{},
 	}
+	if icon, ok := elionaIcon(template.Icon, iconMapping); ok {
+		apiAsset.Icon = *api.NewNullableString(&icon)
+	}
 
+	qualityAttributes := make(map[string]bool)
 	for _, dp := range template.Datapoints {
-		subtype := determineSubtype(dp.Direction)
-		var attributes []attributeTemplateInfo
+		subtype := determineSubtype(ParseDirection(dp.Direction))
 		for _, attrib := range dp.Attributes {
-			mapping := convertMapping(attrib.Enums)
+			mapping := convertMapping(attrib.Enums, attrib.Format)
 
 			// Only set nillables if they are not nil
 			var min api.NullableFloat64
@@ -80,25 +75,21 @@ func convertAssetTemplateToAssetType(template assetTemplate) api.AssetType {
 				Unit:    unit,
 				Map:     mapping,
 			}
+			applyFormat(&attribute, attrib.Format)
 			apiAsset.Attributes = append(apiAsset.Attributes, attribute)
-			attributes = append(attributes, attributeTemplateInfo{
-				name: attrib.Name,
-			})
 		}
-		// [datapoint-attribution]
-		datapointBelongingToAssetTemplate[dp.ID] = datapointTemplatePreprocessedInfo{
-			name:       dp.Name,
-			subtype:    string(subtype),
-			attributes: attributes,
+		if !qualityAttributes[dp.Name] {
+			// Datapoints sharing a name also share the quality attribute.
+			qualityAttributes[dp.Name] = true
+			apiAsset.Attributes = append(apiAsset.Attributes, qualityAttribute(dp.Name))
 		}
 	}
 
 	// Properties are attributes that don't change often (our status subtype)
 	for _, prop := range template.Properties {
 		subtype := api.SUBTYPE_STATUS
-		var attributes []attributeTemplateInfo
 		for _, attrib := range prop.Attributes {
-			mapping := convertMapping(attrib.Enums)
+			mapping := convertMapping(attrib.Enums, attrib.Format)
 
 			// Only set nillables if they are not nil
 			var min api.NullableFloat64
@@ -122,16 +113,13 @@ func convertAssetTemplateToAssetType(template assetTemplate) api.AssetType {
 				Unit:    unit,
 				Map:     mapping,
 			}
+			applyFormat(&attribute, attrib.Format)
 			apiAsset.Attributes = append(apiAsset.Attributes, attribute)
-			attributes = append(attributes, attributeTemplateInfo{
-				name: attrib.Name,
-			})
 		}
-		// [datapoint-attribution]
-		datapointBelongingToAssetTemplate[prop.ID] = datapointTemplatePreprocessedInfo{
-			name:       prop.Name,
-			subtype:    string(subtype),
-			attributes: attributes,
+		// Live data of properties carries a quality as well.
+		if !qualityAttributes[prop.Name] {
+			qualityAttributes[prop.Name] = true
+			apiAsset.Attributes = append(apiAsset.Attributes, qualityAttribute(prop.Name))
 		}
 	}
 
@@ -158,89 +146,209 @@ func convertAssetTemplateToAssetType(template assetTemplate) api.AssetType {
 	return apiAsset
 }
 
-func determineSubtype(direction string) api.DataSubtype {
-	switch strings.ToLower(direction) {
-	case "feedback":
+// qualityAttribute shows whether the values of a datapoint can be trusted.
+// It is only written if the quality changes.
+func qualityAttribute(datapointName string) api.AssetTypeAttribute {
+	return api.AssetTypeAttribute{
+		Name:    appmodel.QualityAttributeName(datapointName),
+		Subtype: api.SUBTYPE_STATUS,
+		Map: []map[string]any{
+			{
+				"value": appmodel.QualityGood,
+				"map":   "Good",
+			},
+			{
+				"value": appmodel.QualityUncertain,
+				"map":   "Uncertain",
+			},
+			{
+				"value": appmodel.QualityBad,
+				"map":   "Bad",
+			},
+		},
+	}
+}
+
+func determineSubtype(direction Direction) api.DataSubtype {
+	switch direction {
+	case DirectionFeedback:
 		return api.SUBTYPE_INPUT
-	case "command", "commandandfeedback":
+	case DirectionCommand, DirectionCommandAndFeedback:
 		return api.SUBTYPE_OUTPUT
 	default:
 		return api.SUBTYPE_INFO
 	}
 }
 
-func convertMapping(enum map[string]string) []map[string]any {
-	var mapping []map[string]any
-	for key, value := range enum {
-		mapping = append(mapping, map[string]any{
-			"value": key,
-			"map":   value,
-		})
+// convertMapping converts enums to the value mapping of an attribute. Values
+// are typed like the live data of the format, so that numeric states match
+// numeric values, and sorted, so that the mapping is the same on every sync.
+func convertMapping(enums map[string]enumLabel, format Format) []map[string]any {
+	if len(enums) == 0 {
+		return nil
+	}
+	mapping := make([]map[string]any, 0, len(enums))
+	for key, label := range enums {
+		value, err := toElionaValue(format, key)
+		if err != nil {
+			value = key
+		}
+		entry := map[string]any{
+			"value": value,
+			"map":   label.text(),
+		}
+		if translation := label.translation(); translation != nil {
+			entry["translation"] = translation
+		}
+		mapping = append(mapping, entry)
 	}
+	slices.SortFunc(mapping, func(a, b map[string]any) int {
+		return compareEnumValues(a["value"], b["value"])
+	})
 	return mapping
 }
 
-func FetchOntology(config appmodel.Configuration) (ontologyVersion int32, assetTypes []api.AssetType, root eliona.Asset, err error) {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+// compareEnumValues orders numbers numerically and before texts.
+func compareEnumValues(a, b any) int {
+	x, xErr := toNumber(a)
+	y, yErr := toNumber(b)
+	switch {
+	case xErr == nil && yErr == nil:
+		return cmp.Compare(x, y)
+	case xErr == nil:
+		return -1
+	case yErr == nil:
+		return 1
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// [datapoint-attribution] attributeDatapoints assigns datapoints and properties
+// to assets and spaces. Datapoints belonging to neither are returned.
+func (ontology *ontologyDTO) attributeDatapoints() (orphanDatapoints []ontologyDatapointDTO) {
+	datapointsMap := make(map[string][]ontologyDatapointDTO)
+	for _, datapointDTO := range ontology.Datapoints {
+		if datapointDTO.AssetID != "" {
+			datapointsMap[datapointDTO.AssetID] = append(datapointsMap[datapointDTO.AssetID], datapointDTO)
+		}
+		if datapointDTO.SpaceID != "" {
+			datapointsMap[datapointDTO.SpaceID] = append(datapointsMap[datapointDTO.SpaceID], datapointDTO)
+		}
+		if datapointDTO.AssetID == "" && datapointDTO.SpaceID == "" {
+			orphanDatapoints = append(orphanDatapoints, datapointDTO)
+		}
+	}
+	propertiesMap := make(map[string][]ontologyPropertyDTO)
+	for _, propertyDTO := range ontology.Properties {
+		if propertyDTO.AssetID != "" {
+			propertiesMap[propertyDTO.AssetID] = append(propertiesMap[propertyDTO.AssetID], propertyDTO)
+		}
+		if propertyDTO.SpaceID != "" {
+			propertiesMap[propertyDTO.SpaceID] = append(propertiesMap[propertyDTO.SpaceID], propertyDTO)
+		}
+	}
+	for i, asset := range ontology.Assets {
+		ontology.Assets[i].datapoints = datapointsMap[asset.ID]
+		ontology.Assets[i].properties = propertiesMap[asset.ID]
+	}
+
+	for i, space := range ontology.Spaces {
+		ontology.Spaces[i].datapoints = datapointsMap[space.ID]
+		ontology.Spaces[i].properties = propertiesMap[space.ID]
+	}
+	return orphanDatapoints
+}
+
+func (ontology ontologyDTO) knownIDs() map[string]bool {
+	ids := make(map[string]bool)
+	for _, asset := range ontology.Assets {
+		ids[asset.ID] = true
+	}
+	for _, space := range ontology.Spaces {
+		ids[space.ID] = true
+	}
+	for _, datapoint := range ontology.Datapoints {
+		ids[datapoint.ID] = true
+	}
+	for _, property := range ontology.Properties {
+		ids[property.ID] = true
+	}
+	return ids
+}
+
+// OntologyUpdate is the result of fetching a new version of the ontology.
+type OntologyUpdate struct {
+	Version int32
+
+	// AssetTypes holds the asset types of templates that were added or changed.
+	AssetTypes []api.AssetType
+	Root       eliona.Asset
+	Diff       appmodel.OntologyDiff
+
+	// KnownIDs holds the IDs of all assets, spaces, datapoints and properties
+	// in the ontology, including those excluded by the asset filter.
+	KnownIDs map[string]bool
+
+	// Snapshot is the fetched ontology, to be persisted once the update is
+	// applied and passed to the next call of FetchOntology.
+	Snapshot []byte
+}
+
+// FetchOntology fetches the ontology if its version differs from the one last
+// synchronized, and compares it with the snapshot of that last synchronization.
+// Without a snapshot, the whole ontology is reported as added.
+func FetchOntology(ctx context.Context, config appmodel.Configuration, snapshot []byte) (OntologyUpdate, error) {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return 0, nil, eliona.Asset{}, fmt.Errorf("creating instance of client: %v", err)
+		return OntologyUpdate{}, fmt.Errorf("getting instance of client: %v", err)
 	}
 
-	version, err := client.getOntologyVersion()
+	version, err := client.getOntologyVersion(ctx)
 	if err != nil {
-		return 0, nil, eliona.Asset{}, fmt.Errorf("getting ontology version: %v", err)
+		return OntologyUpdate{}, fmt.Errorf("getting ontology version: %v", err)
 	}
 	if version == config.OntologyVersion {
-		return 0, nil, eliona.Asset{}, ErrNoUpdate
+		return OntologyUpdate{}, ErrNoUpdate
 	}
 
-	ontology, err := client.getOntology()
+	ontology, err := client.getOntology(ctx)
+	if err != nil {
+		return OntologyUpdate{}, fmt.Errorf("getting ontology: %v", err)
+	}
+	newSnapshot, err := json.Marshal(ontology)
 	if err != nil {
-		return 0, nil, eliona.Asset{}, fmt.Errorf("getting ontology: %v", err)
+		return OntologyUpdate{}, fmt.Errorf("marshalling ontology snapshot: %v", err)
 	}
 
-	// [datapoint-attribution] Assign datapoints and properties to assets and spaces
-	var orphanDatapoints []ontologyDatapointDTO
-	{
-		datapointsMap := make(map[string][]ontologyDatapointDTO)
-		for _, datapointDTO := range ontology.Datapoints {
-			if datapointDTO.AssetID != "" {
-				datapointsMap[datapointDTO.AssetID] = append(datapointsMap[datapointDTO.AssetID], datapointDTO)
-			}
-			if datapointDTO.SpaceID != "" {
-				datapointsMap[datapointDTO.SpaceID] = append(datapointsMap[datapointDTO.SpaceID], datapointDTO)
-			}
-			if datapointDTO.AssetID == "" && datapointDTO.SpaceID == "" {
-				orphanDatapoints = append(orphanDatapoints, datapointDTO)
+	orphanDatapoints := ontology.attributeDatapoints()
+	model := newOntologyModel(*ontology, orphanDatapoints, config)
+
+	diff := fullDiff(*ontology, model.templates)
+	if snapshot != nil {
+		var previous ontologyDTO
+		if err := json.Unmarshal(snapshot, &previous); err != nil {
+			log.Warn("broker", "ignoring unreadable ontology snapshot of config %d: %v", config.Id, err)
+		} else {
+			previousModel := newOntologyModel(previous, previous.attributeDatapoints(), config)
+			diff = diffOntologies(previous, *ontology, previousModel.templates, model.templates)
+			for templateID, attributes := range removedAttributes(previousModel.templates, model.templates) {
+				log.Info("broker", "attributes %v were removed from template %v, they stay in the Eliona asset type but receive no more data", attributes, templateID)
 			}
 		}
-		propertiesMap := make(map[string][]ontologyPropertyDTO)
-		for _, propertyDTO := range ontology.Properties {
-			if propertyDTO.AssetID != "" {
-				propertiesMap[propertyDTO.AssetID] = append(propertiesMap[propertyDTO.AssetID], propertyDTO)
-			}
-			if propertyDTO.SpaceID != "" {
-				propertiesMap[propertyDTO.SpaceID] = append(propertiesMap[propertyDTO.SpaceID], propertyDTO)
-			}
-		}
-		for i, asset := range ontology.Assets {
-			ontology.Assets[i].datapoints = datapointsMap[asset.ID]
-			ontology.Assets[i].properties = propertiesMap[asset.ID]
-		}
-
-		for i, space := range ontology.Spaces {
-			ontology.Spaces[i].datapoints = datapointsMap[space.ID]
-			ontology.Spaces[i].properties = propertiesMap[space.ID]
-		}
 	}
 
-	ats := ontology.getAssetTemplates(orphanDatapoints)
-	for _, assetTemplate := range ats {
-		assetType := convertAssetTemplateToAssetType(assetTemplate)
-		assetTypes = append(assetTypes, assetType)
+	updatedTemplates := make(map[string]bool)
+	for _, id := range append(diff.Templates.Added, diff.Templates.Changed...) {
+		updatedTemplates[id] = true
+	}
+	var assetTypes []api.AssetType
+	for _, assetTemplate := range model.templates {
+		if updatedTemplates[assetTemplate.ID] {
+			assetTypes = append(assetTypes, convertAssetTemplateToAssetType(assetTemplate, config.IconMapping))
+		}
 	}
 
-	root = eliona.Asset{
+	root := eliona.Asset{
 		ID:                    "",
 		TemplateID:            "root",
 		Name:                  "OpenBOS",
@@ -272,7 +380,7 @@ func FetchOntology(config appmodel.Configuration) (ontologyVersion int32, assetT
 	}
 
 	// Build the asset hierarchy based on spaces
-	buildAssetHierarchy(&root, spaces, assetsMap, config)
+	buildAssetHierarchy(&root, spaces, assetsMap, model, config)
 
 	// Handle assets not associated with any space
 	associatedAssetIDs := make(map[string]struct{})
@@ -282,21 +390,27 @@ func FetchOntology(config appmodel.Configuration) (ontologyVersion int32, assetT
 		}
 	}
 	for _, asset := range ontology.Assets {
-		if _, associated := associatedAssetIDs[asset.ID]; !associated {
-			// Asset not associated with any space; add to root
-			root.FunctionalChildrenSlice = append(root.FunctionalChildrenSlice, eliona.Asset{
-				ID:         asset.ID,
-				Name:       asset.Name,
-				TemplateID: asset.TemplateID,
-				Config:     &config,
-			})
+		if _, associated := associatedAssetIDs[asset.ID]; associated {
+			continue
+		}
+		// Asset not associated with any space; placed in the root like the
+		// top-level spaces. Without a space, there is no master.
+		if assetInstance, ok := buildAsset(assetsMap[asset.ID], 0, model, config); ok {
+			root.LocationalChildrenMap[asset.ID] = assetInstance
 		}
 	}
 
-	return version, assetTypes, root, nil
+	return OntologyUpdate{
+		Version:    version,
+		AssetTypes: assetTypes,
+		Root:       root,
+		Diff:       diff,
+		KnownIDs:   ontology.knownIDs(),
+		Snapshot:   newSnapshot,
+	}, nil
 }
 
-func buildAssetHierarchy(asset *eliona.Asset, spaces map[string]*ontologySpaceDTO, assetsMap map[string]ontologyAssetDTO, config appmodel.Configuration) {
+func buildAssetHierarchy(asset *eliona.Asset, spaces map[string]*ontologySpaceDTO, assetsMap map[string]ontologyAssetDTO, model *ontologyModel, config appmodel.Configuration) {
 	space, exists := spaces[asset.ID]
 	if !exists {
 		log.Error("broker", "Should not happen: space %s not found.", asset.ID)
@@ -305,76 +419,14 @@ func buildAssetHierarchy(asset *eliona.Asset, spaces map[string]*ontologySpaceDT
 	// Process child spaces
 	for _, childSpace := range space.children {
 
-		// [datapoint-attribution]
-		// We need to merge attribute template information (name, subtype) with attribute instance information (instanceID, asset ID)
-		var dps []appmodel.Datapoint
-		for _, dp := range childSpace.datapoints {
-			datapoint, ok := datapointBelongingToAssetTemplate[dp.TemplateID]
-			if !ok {
-				log.Warn("broker", "datapoint not found for datapoint template %s", dp.TemplateID)
-				continue
-			}
-			var attributes []appmodel.Attribute
-			for _, attributeInfo := range datapoint.attributes {
-				attributes = append(attributes, appmodel.Attribute{
-					Name: attributeInfo.name,
-				})
-			}
-			dps = append(dps, appmodel.Datapoint{
-				Subtype:             datapoint.subtype,
-				ProviderID:          dp.ID,
-				AttributeNamePrefix: datapoint.name,
-				Attributes:          attributes,
-			})
-		}
-		for _, prop := range childSpace.properties {
-			datapoint, ok := datapointBelongingToAssetTemplate[prop.TemplateID]
-			if !ok {
-				log.Warn("broker", "datapoint not found for property template %s", prop.TemplateID)
-				continue
-			}
-			var attributes []appmodel.Attribute
-			for _, attributeInfo := range datapoint.attributes {
-				attributes = append(attributes, appmodel.Attribute{
-					Name: attributeInfo.name,
-				})
-			}
-			dp := appmodel.Datapoint{
-				Subtype:             datapoint.subtype,
-				ProviderID:          prop.ID,
-				AttributeNamePrefix: datapoint.name,
-				Attributes:          attributes,
-			}
-
-			if prop.Value != nil {
-				assetData := make(map[string]any)
-				// Complex decode support
-				if complexData, ok := prop.Value.(map[string]any); ok {
-					decodedData := complexdata.DecodeComplexData(complexData, dp.AttributeNamePrefix)
-					for k, v := range decodedData {
-						assetData[k] = v
-					}
-				} else {
-					// If not complex, find the attribute name and map directly
-					if len(dp.Attributes) != 1 {
-						log.Error("inconsistency", "received non-complex data %+v for property %v of datapoint %v, but found datapoint providerID %v with %v != 1 attributes", prop.Value, prop.ID, datapoint.name, dp.ProviderID, len(dp.Attributes))
-						continue
-					}
-					assetData[dp.Attributes[0].Name] = prop.Value
-				}
-				dp.Data = assetData
-			}
-
-			dps = append(dps, dp)
-		}
-
 		childAsset := eliona.Asset{
 			ID:                    childSpace.ID,
 			Name:                  childSpace.Name,
 			TemplateID:            childSpace.TemplateID,
 			Config:                &config,
 			LocationalChildrenMap: make(map[string]eliona.Asset),
-			Datapoints:            dps,
+			Datapoints:            model.datapoints(childSpace.datapoints, childSpace.properties),
+			Tags:                  config.ElionaTags(model.templateTags[childSpace.TemplateID], childSpace.Tags),
 		}
 		if adheres, err := childAsset.AdheresToFilter(config.AssetFilter); err != nil {
 			log.Error("broker", "checking if space adheres to filter: %v", err)
@@ -383,136 +435,212 @@ func buildAssetHierarchy(asset *eliona.Asset, spaces map[string]*ontologySpaceDT
 			log.Debug("broker", "skipped space ID %v name '%v' due to asset filter rule.", childSpace.ID, childSpace.Name)
 			continue
 		}
-		buildAssetHierarchy(&childAsset, spaces, assetsMap, config)
+		buildAssetHierarchy(&childAsset, spaces, assetsMap, model, config)
 		asset.LocationalChildrenMap[childSpace.ID] = childAsset
-		// todo: add functional slice here as well
 	}
-	// Process assets associated with this space
+	// Process assets associated with this space. If the space has a master
+	// asset, the other assets become its functional children.
+	masterID := space.masterAssetID()
+	var slaves []eliona.Asset
 	for _, spaceAsset := range space.Assets {
 		assetDetails, exists := assetsMap[spaceAsset.ID]
 		if !exists {
 			log.Warn("broker", "asset %s in space %s not found. Skipping.", spaceAsset.ID, space.ID)
 			continue // Asset not found; skip
 		}
-
-		// [datapoint-attribution]
-		// We need to merge attribute template information (name, subtype) with attribute instance information (instanceID, asset ID)
-		var dps []appmodel.Datapoint
-		for _, dp := range assetDetails.datapoints {
-			datapoint, ok := datapointBelongingToAssetTemplate[dp.TemplateID]
-			if !ok {
-				log.Warn("broker", "datapoint template not found for datapoint template %s", dp.TemplateID)
-				continue
-			}
-			var attributes []appmodel.Attribute
-			for _, attributeInfo := range datapoint.attributes {
-				attributes = append(attributes, appmodel.Attribute{
-					Name: attributeInfo.name,
-				})
-			}
-			dps = append(dps, appmodel.Datapoint{
-				Subtype:             datapoint.subtype,
-				ProviderID:          dp.ID,
-				AttributeNamePrefix: datapoint.name,
-				Attributes:          attributes,
-			})
-		}
-		for _, prop := range assetDetails.properties {
-			datapoint, ok := datapointBelongingToAssetTemplate[prop.TemplateID]
-			if !ok {
-				log.Warn("broker", "datapoint template not found for property template %s", prop.TemplateID)
-				continue
-			}
-			var attributes []appmodel.Attribute
-			for _, attributeInfo := range datapoint.attributes {
-				attributes = append(attributes, appmodel.Attribute{
-					Name: attributeInfo.name,
-				})
-			}
-			dp := appmodel.Datapoint{
-				Subtype:             datapoint.subtype,
-				ProviderID:          prop.ID,
-				AttributeNamePrefix: datapoint.name,
-				Attributes:          attributes,
-			}
-
-			if prop.Value != nil {
-				assetData := make(map[string]any)
-				// Complex decode support
-				if complexData, ok := prop.Value.(map[string]any); ok {
-					decodedData := complexdata.DecodeComplexData(complexData, dp.AttributeNamePrefix)
-					for k, v := range decodedData {
-						assetData[k] = v
-					}
-				} else {
-					// If not complex, find the attribute name and map directly
-					if len(dp.Attributes) != 1 {
-						log.Error("inconsistency", "received non-complex data %+v for property %v of datapoint %v, but found datapoint providerID %v with %v != 1 attributes", prop.Value, prop.ID, assetDetails.ID, dp.ProviderID, len(dp.Attributes))
-						continue
-					}
-					assetData[dp.Attributes[0].Name] = prop.Value
-				}
-				dp.Data = assetData
-			}
-			dps = append(dps, dp)
-		}
-
 		isMaster := int8(0)
 		if spaceAsset.Master {
 			isMaster = 1
 		}
-		assetInstance := eliona.Asset{
-			ID:         assetDetails.ID,
-			Name:       assetDetails.Name,
-			TemplateID: assetDetails.TemplateID,
-			Config:     &config,
-			Datapoints: dps,
-
-			IsMaster: isMaster,
-		}
-		if adheres, err := assetInstance.AdheresToFilter(config.AssetFilter); err != nil {
-			log.Error("broker", "checking if asset adheres to filter: %v", err)
+		assetInstance, ok := buildAsset(assetDetails, isMaster, model, config)
+		if !ok {
 			continue
-		} else if !adheres {
-			log.Debug("broker", "skipped asset ID %v name '%v' due to asset filter rule.", assetInstance.ID, assetInstance.Name)
+		}
+		if masterID != "" && spaceAsset.ID != masterID {
+			slaves = append(slaves, assetInstance)
 			continue
 		}
 		asset.LocationalChildrenMap[spaceAsset.ID] = assetInstance
-		// todo: add functional slice here as well
 	}
+	for _, slave := range slaves {
+		master, ok := asset.LocationalChildrenMap[masterID]
+		if !ok {
+			// The master is excluded by the asset filter.
+			asset.LocationalChildrenMap[slave.ID] = slave
+			continue
+		}
+		master.FunctionalChildrenSlice = append(master.FunctionalChildrenSlice, slave)
+		asset.LocationalChildrenMap[masterID] = master
+	}
+}
+
+// buildAsset converts an OpenBOS asset to an Eliona asset with its datapoints
+// and properties. Returns false if the asset is excluded by the asset filter.
+func buildAsset(assetDetails ontologyAssetDTO, isMaster int8, model *ontologyModel, config appmodel.Configuration) (eliona.Asset, bool) {
+	assetInstance := eliona.Asset{
+		ID:         assetDetails.ID,
+		Name:       assetDetails.Name,
+		TemplateID: assetDetails.TemplateID,
+		Config:     &config,
+		Datapoints: model.datapoints(assetDetails.datapoints, assetDetails.properties),
+		Tags:       config.ElionaTags(model.templateTags[assetDetails.TemplateID], assetDetails.Tags),
+
+		IsMaster: isMaster,
+	}
+	if adheres, err := assetInstance.AdheresToFilter(config.AssetFilter); err != nil {
+		log.Error("broker", "checking if asset adheres to filter: %v", err)
+		return eliona.Asset{}, false
+	} else if !adheres {
+		log.Debug("broker", "skipped asset ID %v name '%v' due to asset filter rule.", assetInstance.ID, assetInstance.Name)
+		return eliona.Asset{}, false
+	}
+	return assetInstance, true
 }
 
-func SubscribeToOntologyChanges(config appmodel.Configuration) error {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+func SubscribeToOntologyChanges(ctx context.Context, config appmodel.Configuration) (appmodel.Subscription, error) {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return fmt.Errorf("creating instance of client: %v", err)
+		return appmodel.Subscription{}, fmt.Errorf("getting instance of client: %v", err)
 	}
-	if _, err := client.subscribeToOntologyChanges(config.Id); err != nil {
-		return fmt.Errorf("subscribing: %v", err)
+	result, err := client.subscribeToOntologyChanges(ctx)
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("subscribing: %v", err)
 	}
-	return nil
+	return client.toAppSubscription(appmodel.SubscriptionKindOntology, result)
 }
 
-func SubscribeToDataChanges(config appmodel.Configuration) error {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+func SubscribeToDataChanges(ctx context.Context, config appmodel.Configuration) (appmodel.Subscription, error) {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return fmt.Errorf("creating instance of client: %v", err)
+		return appmodel.Subscription{}, fmt.Errorf("getting instance of client: %v", err)
 	}
-	if err := client.subscribeToDataChanges(config.Id); err != nil {
-		return fmt.Errorf("subscribing: %v", err)
+	result, err := client.subscribeToDataChanges(ctx, desiredUnits(config))
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("subscribing: %v", err)
 	}
-	return nil
+	subscription, err := client.toAppSubscription(appmodel.SubscriptionKindData, result)
+	if err != nil {
+		return appmodel.Subscription{}, err
+	}
+	// Fetch the current values, the subscription only reports changes.
+	if err := client.refreshDataSubscription(ctx, subscription.WebhookURL); err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("refreshing: %v", err)
+	}
+	return subscription, nil
+}
+
+// desiredUnits returns the units the edge should convert live data to.
+func desiredUnits(config appmodel.Configuration) []string {
+	var units []string
+	for _, unit := range config.UnitPreferences {
+		if !slices.Contains(units, unit) {
+			units = append(units, unit)
+		}
+	}
+	slices.Sort(units)
+	return units
 }
 
-func SubscribeToAlarms(config appmodel.Configuration) error {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+func SubscribeToAlarms(ctx context.Context, config appmodel.Configuration) (appmodel.Subscription, error) {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return fmt.Errorf("creating instance of client: %v", err)
+		return appmodel.Subscription{}, fmt.Errorf("getting instance of client: %v", err)
 	}
-	if err := client.subscribeToAlarmChanges(config.Id); err != nil {
-		return fmt.Errorf("subscribing: %v", err)
+	result, err := client.subscribeToAlarmChanges(ctx)
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("subscribing: %v", err)
 	}
-	return nil
+	return client.toAppSubscription(appmodel.SubscriptionKindAlarm, result)
+}
+
+// RenewSubscription subscribes again with the same webhook URL, which extends
+// the lease of the subscription on the edge. Unlike subscribing to data changes
+// initially, it does not make the edge resend all current values.
+func RenewSubscription(ctx context.Context, config appmodel.Configuration, kind appmodel.SubscriptionKind) (appmodel.Subscription, error) {
+	client, err := getClient(ctx, config)
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("getting instance of client: %v", err)
+	}
+	var result *subscriptionResultDTO
+	switch kind {
+	case appmodel.SubscriptionKindOntology:
+		result, err = client.subscribeToOntologyChanges(ctx)
+	case appmodel.SubscriptionKindData:
+		result, err = client.subscribeToDataChanges(ctx, desiredUnits(config))
+	case appmodel.SubscriptionKindAlarm:
+		result, err = client.subscribeToAlarmChanges(ctx)
+	default:
+		return appmodel.Subscription{}, fmt.Errorf("unknown subscription kind %q", kind)
+	}
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("renewing: %v", err)
+	}
+	return client.toAppSubscription(kind, result)
+}
+
+func (c *openBOSClient) toAppSubscription(kind appmodel.SubscriptionKind, result *subscriptionResultDTO) (appmodel.Subscription, error) {
+	now := time.Now()
+	subscription := appmodel.Subscription{Kind: kind, SubscribedAt: now, RenewedAt: now}
+	if kind != appmodel.SubscriptionKindOntology {
+		subscription.LeaseTime = subscriptionLeaseTime
+		subscription.MaxSendTime = subscriptionMaxSendTime
+	}
+	if result.ID != nil {
+		subscription.SubscriptionID = *result.ID
+	}
+	if result.WebHookURL != nil {
+		subscription.WebhookURL = *result.WebHookURL
+		return subscription, nil
+	}
+	webhookURL, err := c.subscriptionWebhookURL(kind)
+	if err != nil {
+		return appmodel.Subscription{}, fmt.Errorf("joining URL for subscription: %v", err)
+	}
+	subscription.WebhookURL = webhookURL
+	return subscription, nil
+}
+
+// Unsubscribe removes all webhook subscriptions of the configuration from the
+// edge. Stored subscriptions are deleted by their ID, kinds without a stored
+// subscription by the webhook URL the app would have subscribed with. All
+// kinds are attempted even if one fails.
+func Unsubscribe(ctx context.Context, config appmodel.Configuration, subscriptions []appmodel.Subscription) error {
+	client, err := getClient(ctx, config)
+	if err != nil {
+		return fmt.Errorf("getting instance of client: %v", err)
+	}
+	stored := make(map[appmodel.SubscriptionKind]appmodel.Subscription)
+	for _, subscription := range subscriptions {
+		stored[subscription.Kind] = subscription
+	}
+	var errs []error
+	for _, kind := range appmodel.SubscriptionKinds {
+		subscription, ok := stored[kind]
+		if !ok {
+			webhookURL, err := client.subscriptionWebhookURL(kind)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("joining URL for %s subscription: %v", kind, err))
+				continue
+			}
+			subscription = appmodel.Subscription{Kind: kind, WebhookURL: webhookURL}
+		}
+		if err := client.removeSubscription(ctx, subscription); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		log.Debug("broker", "Removed %s subscription of config %d", kind, config.Id)
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveSubscription removes a single webhook subscription from the edge, e.g.
+// one that was replaced by a subscription with a different webhook URL.
+func RemoveSubscription(ctx context.Context, config appmodel.Configuration, subscription appmodel.Subscription) error {
+	client, err := getClient(ctx, config)
+	if err != nil {
+		return fmt.Errorf("getting instance of client: %v", err)
+	}
+	return client.removeSubscription(ctx, subscription)
 }
 
 type AttributeData struct {
@@ -520,18 +648,18 @@ type AttributeData struct {
 	Value     any
 }
 
-func PutData(config appmodel.Configuration, attributesData []AttributeData) error {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+func PutData(ctx context.Context, config appmodel.Configuration, attributesData []AttributeData) error {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return fmt.Errorf("creating instance of client: %v", err)
+		return fmt.Errorf("getting instance of client: %v", err)
 	}
-	return client.putData(attributesData)
+	return client.putData(ctx, attributesData)
 }
 
-func AcknowledgeAlarm(config appmodel.Configuration, sessionID, ackedBy, comment string) error {
-	client, err := newOpenBOSClient(config.Gwid, config.ClientID, config.ClientSecret, config.AppPublicAPIURL, baseURL, tokenURL)
+func AcknowledgeAlarm(ctx context.Context, config appmodel.Configuration, sessionID, ackedBy, comment string) error {
+	client, err := getClient(ctx, config)
 	if err != nil {
-		return fmt.Errorf("creating instance of client: %v", err)
+		return fmt.Errorf("getting instance of client: %v", err)
 	}
 
 	ack := ontologyAlarmAckDTO{
@@ -542,7 +670,7 @@ func AcknowledgeAlarm(config appmodel.Configuration, sessionID, ackedBy, comment
 
 	log.Debug("client", "Acknowledging alarm with session ID: %s", sessionID)
 
-	if err := client.ackAlarm(ack); err != nil {
+	if err := client.ackAlarm(ctx, ack); err != nil {
 		log.Error("client", "Failed to acknowledge alarm: %v", err)
 		return fmt.Errorf("acknowledging alarm: %v", err)
 	}
@@ -1,12 +1,17 @@
 package broker
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	appmodel "open-bos/app/model"
+	"open-bos/eliona"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
 	"github.com/eliona-smart-building-assistant/go-utils/common"
@@ -56,7 +61,7 @@ func TestFetchOntology(t *testing.T) {
 
 	// Create a custom newOpenBOSClient function for testing
 	originalNewOpenBOSClient := newOpenBOSClient
-	newOpenBOSClient = func(gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL string) (*openBOSClient, error) {
+	newOpenBOSClient = func(ctx context.Context, gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL, scope string, timeout time.Duration, retry retryPolicy) (*openBOSClient, error) {
 		client := &openBOSClient{
 			gatewayID:    gatewayID,
 			httpClient:   ts.Client(),
@@ -73,9 +78,11 @@ func TestFetchOntology(t *testing.T) {
 		return client, nil
 	}
 	defer func() { newOpenBOSClient = originalNewOpenBOSClient }()
+	defer clients.remove(config.Id)
 
 	// Call FetchOntology
-	ontologyVersion, assetTypes, rootAsset, err := FetchOntology(config)
+	update, err := FetchOntology(context.Background(), config, nil)
+	ontologyVersion, assetTypes, rootAsset := update.Version, update.AssetTypes, update.Root
 	if err != nil {
 		t.Fatalf("FetchOntology returned error: %v", err)
 	}
@@ -90,9 +97,28 @@ func TestFetchOntology(t *testing.T) {
 		}),
 		Attributes: []api.AssetTypeAttribute{
 			{
-				Name:    "Temperature",
-				Subtype: api.SUBTYPE_INPUT,
-				Unit:    *api.NewNullableString(common.Ptr("°C")),
+				Name:      "Temperature",
+				Subtype:   api.SUBTYPE_INPUT,
+				Unit:      *api.NewNullableString(common.Ptr("°C")),
+				Precision: *api.NewNullableInt64(common.Ptr(int64(2))),
+			},
+			{
+				Name:    "Temperature_quality",
+				Subtype: api.SUBTYPE_STATUS,
+				Map: []map[string]interface{}{
+					{
+						"value": 0,
+						"map":   "Good",
+					},
+					{
+						"value": 1,
+						"map":   "Uncertain",
+					},
+					{
+						"value": 2,
+						"map":   "Bad",
+					},
+				},
 			},
 			{
 				Name:      masterPropertyAttribute,
@@ -129,12 +155,13 @@ func TestFetchOntology(t *testing.T) {
 		t.Errorf("Expected root asset name 'OpenBOS', got '%s'", rootAsset.Name)
 	}
 
-	// Check that the asset hierarchy is correctly built
-	if len(rootAsset.FunctionalChildrenSlice) != 1 {
-		t.Errorf("Expected 1 child asset, got %d", len(rootAsset.FunctionalChildrenSlice))
+	// Check that the asset hierarchy is correctly built. Assets outside of any
+	// space are placed directly below the root.
+	if len(rootAsset.LocationalChildrenMap) != 1 {
+		t.Fatalf("Expected 1 child asset, got %d", len(rootAsset.LocationalChildrenMap))
 	}
 
-	childAsset := rootAsset.FunctionalChildrenSlice[0]
+	childAsset := rootAsset.LocationalChildrenMap["asset-1"]
 	if childAsset.Name != "Sensor 1" {
 		t.Errorf("Expected child asset name 'Sensor 1', got '%s'", childAsset.Name)
 	}
@@ -277,7 +304,7 @@ func TestFetchOntologyWithComplexDataTypes(t *testing.T) {
 
 	// Override newOpenBOSClient in the test
 	originalNewOpenBOSClient := newOpenBOSClient
-	newOpenBOSClient = func(gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL string) (*openBOSClient, error) {
+	newOpenBOSClient = func(ctx context.Context, gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL, scope string, timeout time.Duration, retry retryPolicy) (*openBOSClient, error) {
 		client := &openBOSClient{
 			gatewayID:    gatewayID,
 			httpClient:   ts.Client(),
@@ -291,9 +318,11 @@ func TestFetchOntologyWithComplexDataTypes(t *testing.T) {
 		return client, nil
 	}
 	defer func() { newOpenBOSClient = originalNewOpenBOSClient }()
+	defer clients.remove(config.Id)
 
 	// Call the function under test
-	ontologyVersion, assetTypes, _, err := FetchOntology(config)
+	update, err := FetchOntology(context.Background(), config, nil)
+	ontologyVersion, assetTypes := update.Version, update.AssetTypes
 	if err != nil {
 		t.Fatalf("FetchOntology returned error: %v", err)
 	}
@@ -316,11 +345,11 @@ func TestFetchOntologyWithComplexDataTypes(t *testing.T) {
 			Name:    "HVAC_Status",
 			Subtype: api.SUBTYPE_INPUT,
 			Map: []map[string]interface{}{
-				{"value": "0", "map": "Auto"},
-				{"value": "1", "map": "Comfort"},
-				{"value": "2", "map": "Standby"},
-				{"value": "3", "map": "Economy"},
-				{"value": "4", "map": "Building Protection"},
+				{"value": int64(0), "map": "Auto"},
+				{"value": int64(1), "map": "Comfort"},
+				{"value": int64(2), "map": "Standby"},
+				{"value": int64(3), "map": "Economy"},
+				{"value": int64(4), "map": "Building Protection"},
 			},
 		},
 		{
@@ -334,22 +363,31 @@ func TestFetchOntologyWithComplexDataTypes(t *testing.T) {
 			Name:    "33333333-3333-3333-3333-333333333333.complexInComplex.mySecondField",
 			Subtype: api.SUBTYPE_INPUT,
 			Map: []map[string]interface{}{
-				{"value": "0", "map": "Auto"},
-				{"value": "1", "map": "Comfort"},
-				{"value": "2", "map": "Standby"},
-				{"value": "3", "map": "Economy"},
-				{"value": "4", "map": "Building Protection"},
+				{"value": int64(0), "map": "Auto"},
+				{"value": int64(1), "map": "Comfort"},
+				{"value": int64(2), "map": "Standby"},
+				{"value": int64(3), "map": "Economy"},
+				{"value": int64(4), "map": "Building Protection"},
 			},
 		},
 		{
 			Name:    "33333333-3333-3333-3333-333333333333.mySecondField",
 			Subtype: api.SUBTYPE_INPUT,
 			Map: []map[string]interface{}{
-				{"value": "0", "map": "Auto"},
-				{"value": "1", "map": "Comfort"},
-				{"value": "2", "map": "Standby"},
-				{"value": "3", "map": "Economy"},
-				{"value": "4", "map": "Building Protection"},
+				{"value": int64(0), "map": "Auto"},
+				{"value": int64(1), "map": "Comfort"},
+				{"value": int64(2), "map": "Standby"},
+				{"value": int64(3), "map": "Economy"},
+				{"value": int64(4), "map": "Building Protection"},
+			},
+		},
+		{
+			Name:    "Complex DataPoint_quality",
+			Subtype: api.SUBTYPE_STATUS,
+			Map: []map[string]interface{}{
+				{"value": 0, "map": "Good"},
+				{"value": 1, "map": "Uncertain"},
+				{"value": 2, "map": "Bad"},
 			},
 		},
 		{
@@ -379,8 +417,8 @@ func TestFetchOntologyWithComplexDataTypes(t *testing.T) {
 				assert.Equal(t, expectedAttr.Max, actualAttr.Max, "Max mismatch for %s", expectedAttr.Name)
 				assert.Equal(t, expectedAttr.IsDigital, actualAttr.IsDigital, "IsDigital mismatch for %s", expectedAttr.Name)
 
-				// Use assert.ElementsMatch to compare Map slices regardless of order
-				assert.ElementsMatch(t, expectedAttr.Map, actualAttr.Map, "Map mismatch for %s", expectedAttr.Name)
+				// Mappings are sorted, so that they do not change between syncs.
+				assert.Equal(t, expectedAttr.Map, actualAttr.Map, "Map mismatch for %s", expectedAttr.Name)
 
 				found = true
 				break
@@ -459,7 +497,7 @@ func TestFetchOntologyWithSpaces(t *testing.T) {
 
 	// Override newOpenBOSClient in the test
 	originalNewOpenBOSClient := newOpenBOSClient
-	newOpenBOSClient = func(gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL string) (*openBOSClient, error) {
+	newOpenBOSClient = func(ctx context.Context, gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL, scope string, timeout time.Duration, retry retryPolicy) (*openBOSClient, error) {
 		client := &openBOSClient{
 			gatewayID:    gatewayID,
 			httpClient:   ts.Client(),
@@ -473,9 +511,11 @@ func TestFetchOntologyWithSpaces(t *testing.T) {
 		return client, nil
 	}
 	defer func() { newOpenBOSClient = originalNewOpenBOSClient }()
+	defer clients.remove(config.Id)
 
 	// Call the function under test
-	_, _, rootAsset, err := FetchOntology(config)
+	update, err := FetchOntology(context.Background(), config, nil)
+	rootAsset := update.Root
 	if err != nil {
 		t.Fatalf("FetchOntology returned error: %v", err)
 	}
@@ -498,3 +538,336 @@ func TestFetchOntologyWithSpaces(t *testing.T) {
 	assert.True(t, ok, "'Floor 1' should contain 'Sensor 1' in LocationalChildrenMap")
 	assert.Equal(t, "Sensor 1", sensor1.Name, "Sensor 1 name mismatch")
 }
+
+func TestUnsubscribe(t *testing.T) {
+	var mu sync.Mutex
+	deleted := make(map[string]subscriptionDeleteDTO)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/oauth2/v2.0/token") {
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600}`)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var del subscriptionDeleteDTO
+		if err := json.NewDecoder(r.Body).Decode(&del); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		deleted[strings.TrimPrefix(r.URL.Path, "/gateway/test-gwid/api/v1/")] = del
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	config := appmodel.Configuration{
+		Id:              7,
+		Gwid:            "test-gwid",
+		AppPublicAPIURL: "https://eliona.example/apps-public/open-bos",
+		BaseURL:         ts.URL,
+		TokenURL:        ts.URL + "/oauth2/v2.0/token",
+		RequestTimeout:  10,
+	}
+	defer clients.remove(config.Id)
+
+	stored := []appmodel.Subscription{
+		{Kind: appmodel.SubscriptionKindData, SubscriptionID: "sub-data", WebhookURL: "https://eliona.example/hook"},
+	}
+	err := Unsubscribe(context.Background(), config, stored)
+	assert.NoError(t, err)
+
+	assert.Len(t, deleted, 3)
+	assert.Equal(t, subscriptionDeleteDTO{ID: common.Ptr("sub-data"), WebHookURL: common.Ptr("https://eliona.example/hook")}, deleted["core/application/livedata/subscribe"])
+	// Without a stored subscription the app falls back to its own webhook URL.
+	assert.Nil(t, deleted["core/application/data/version/subscribe"].ID)
+	assert.Equal(t, "https://eliona.example/apps-public/open-bos/7/ontology-version", *deleted["core/application/data/version/subscribe"].WebHookURL)
+	assert.Equal(t, "https://eliona.example/apps-public/open-bos/7/ontology-livealarm", *deleted["core/application/livealarm/subscribe"].WebHookURL)
+}
+
+func TestRenewSubscriptionDoesNotRefresh(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var created subscriptionCreateDTO
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/oauth2/v2.0/token") {
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600}`)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/gateway/test-gwid/api/v1/"))
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&created)
+			fmt.Fprint(w, `{"id": "sub-1"}`)
+		}
+	}))
+	defer ts.Close()
+
+	config := appmodel.Configuration{
+		Id:              8,
+		Gwid:            "test-gwid",
+		AppPublicAPIURL: "https://eliona.example/apps-public/open-bos",
+		BaseURL:         ts.URL,
+		TokenURL:        ts.URL + "/oauth2/v2.0/token",
+		RequestTimeout:  10,
+	}
+	defer clients.remove(config.Id)
+
+	subscription, err := SubscribeToDataChanges(context.Background(), config)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-1", subscription.SubscriptionID)
+	assert.Equal(t, "https://eliona.example/apps-public/open-bos/8/ontology-livedata", subscription.WebhookURL)
+	assert.Equal(t, subscriptionLeaseTime, subscription.LeaseTime)
+	assert.Equal(t, subscriptionMaxSendTime, subscription.MaxSendTime)
+	assert.Equal(t, int32(subscriptionMaxSendTime.Milliseconds()), created.MaxSendTime)
+
+	_, err = RenewSubscription(context.Background(), config, appmodel.SubscriptionKindData)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST core/application/livedata/subscribe",
+		"PUT core/application/livedata/subscribe/refresh",
+		"POST core/application/livedata/subscribe",
+	}, requests)
+}
+
+func TestSubscribeToDataChangesSendsDesiredUnits(t *testing.T) {
+	var created []subscriptionCreateDTO
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/oauth2/v2.0/token") {
+			fmt.Fprint(w, `{"access_token": "test-token", "expires_in": 3600}`)
+			return
+		}
+		if r.Method == http.MethodPost {
+			var sub subscriptionCreateDTO
+			json.NewDecoder(r.Body).Decode(&sub)
+			created = append(created, sub)
+			fmt.Fprint(w, `{"id": "sub-1"}`)
+		}
+	}))
+	defer ts.Close()
+
+	config := appmodel.Configuration{
+		Id:              9,
+		Gwid:            "test-gwid",
+		AppPublicAPIURL: "https://eliona.example/apps-public/open-bos",
+		BaseURL:         ts.URL,
+		TokenURL:        ts.URL + "/oauth2/v2.0/token",
+		RequestTimeout:  10,
+		UnitPreferences: map[string]string{"degF": "degC", "kelvin": "degC", "kBtu": "kWh"},
+	}
+	defer clients.remove(config.Id)
+
+	_, err := SubscribeToDataChanges(context.Background(), config)
+	assert.NoError(t, err)
+	_, err = RenewSubscription(context.Background(), config, appmodel.SubscriptionKindData)
+	assert.NoError(t, err)
+
+	if assert.Len(t, created, 2) {
+		assert.Equal(t, []string{"degC", "kWh"}, created[0].DesiredUnits)
+		assert.Equal(t, []string{"degC", "kWh"}, created[1].DesiredUnits, "renewing must keep the desired units")
+	}
+}
+
+func TestOntologyModelUsesPreferredAndConvertedUnits(t *testing.T) {
+	ontology := ontologyDTO{
+		AssetTemplates: []ontologyAssetOrSpaceTemplateDTO{{ID: "template", Name: "Sensor"}},
+		Units: []ontologyUnitDTO{
+			{ID: "degF", Symbol: "°F"},
+			{ID: "degC", Symbol: "°C"},
+			{ID: "kBtu", Symbol: "kBtu"},
+			{ID: "psi", Symbol: "psi"},
+		},
+		DataTypes: []ontologyDataTypeDTO{
+			{ID: "temperature", Format: "float", UnitID: "degF"},
+			{ID: "energy", Format: "float", UnitID: "kBtu"},
+			{ID: "pressure", Format: "float", UnitID: "psi"},
+		},
+		DatapointTemplates: []ontologyDatapointTemplateDTO{
+			{ID: "dp-temperature", Name: "Temperature", AssetTemplateID: "template", TypeID: "temperature"},
+			{ID: "dp-energy", Name: "Energy", AssetTemplateID: "template", TypeID: "energy"},
+			{ID: "dp-pressure", Name: "Pressure", AssetTemplateID: "template", TypeID: "pressure"},
+		},
+		PropertyTemplates: []ontologyPropertyTemplateDTO{
+			{ID: "prop-setpoint", Name: "Setpoint", AssetTemplateID: "template", TypeID: "temperature"},
+		},
+	}
+
+	model := newOntologyModel(ontology, nil, appmodel.Configuration{
+		UnitPreferences: map[string]string{
+			"degF": "degC",
+			"kBtu": "missing", // Not in the ontology, the original unit is kept.
+		},
+		UnitConversions: []appmodel.UnitConversion{
+			{From: "psi", To: "bar", Factor: 0.0689476}, // Not in the ontology, the ID is used as unit.
+		},
+	})
+
+	var template assetTemplate
+	for _, at := range model.templates {
+		if at.ID == "template" {
+			template = at
+		}
+	}
+	if !assert.Len(t, template.Datapoints, 3) || !assert.Len(t, template.Properties, 1) {
+		return
+	}
+	assert.Equal(t, "°C", *template.Datapoints[0].Attributes[0].DisplayUnitID)
+	assert.Equal(t, "kBtu", *template.Datapoints[1].Attributes[0].DisplayUnitID)
+	assert.Equal(t, "bar", *template.Datapoints[2].Attributes[0].DisplayUnitID)
+	assert.Equal(t, "psi", template.Datapoints[2].Attributes[0].UnitID, "the unit the edge sends is kept for converting")
+	assert.Equal(t, "psi", template.Datapoints[2].Attributes[0].UnitSymbol)
+	assert.Empty(t, template.Properties[0].Attributes[0].UnitID)
+	assert.Equal(t, "°F", *template.Properties[0].Attributes[0].DisplayUnitID, "properties are not converted by the edge")
+}
+
+func TestBuildAssetHierarchyMapsTags(t *testing.T) {
+	config := appmodel.Configuration{TagPrefix: "bos:", TagAllowList: []string{"hvac*", "critical"}}
+	spaces := map[string]*ontologySpaceDTO{
+		"": {children: []ontologySpaceDTO{{
+			ID:         "floor",
+			Name:       "Floor",
+			TemplateID: "floor-template",
+			Tags:       []string{"hvac-zone", "internal"},
+			Assets:     []ontologySpaceAssetDTO{{ID: "ahu"}},
+		}}},
+	}
+	spaces["floor"] = &spaces[""].children[0]
+	assetsMap := map[string]ontologyAssetDTO{
+		"ahu": {ID: "ahu", Name: "AHU", TemplateID: "ahu-template", Tags: []string{"critical", "hvac"}},
+	}
+	model := newOntologyModel(ontologyDTO{
+		AssetTemplates: []ontologyAssetOrSpaceTemplateDTO{{ID: "ahu-template", Tags: []string{"hvac", "vendor-x"}}},
+		SpaceTemplates: []ontologyAssetOrSpaceTemplateDTO{{ID: "floor-template", Tags: []string{"building"}}},
+	}, nil, config)
+
+	root := eliona.Asset{LocationalChildrenMap: make(map[string]eliona.Asset)}
+	buildAssetHierarchy(&root, spaces, assetsMap, model, config)
+
+	floor := root.LocationalChildrenMap["floor"]
+	assert.Equal(t, []string{"bos:hvac-zone"}, floor.Tags)
+	assert.Equal(t, []string{"bos:critical", "bos:hvac"}, floor.LocationalChildrenMap["ahu"].Tags)
+}
+
+func TestBuildAssetHierarchyNestsSlavesBelowMaster(t *testing.T) {
+	spaces := map[string]*ontologySpaceDTO{
+		"": {children: []ontologySpaceDTO{
+			{
+				ID:     "plant-room",
+				Name:   "Plant room",
+				Assets: []ontologySpaceAssetDTO{{ID: "fan"}, {ID: "ahu", Master: true}, {ID: "valve"}},
+			},
+			{
+				ID:     "office",
+				Name:   "Office",
+				Assets: []ontologySpaceAssetDTO{{ID: "lamp"}},
+			},
+		}},
+	}
+	spaces["plant-room"] = &spaces[""].children[0]
+	spaces["office"] = &spaces[""].children[1]
+	assetsMap := map[string]ontologyAssetDTO{
+		"ahu":   {ID: "ahu", Name: "AHU"},
+		"fan":   {ID: "fan", Name: "Fan"},
+		"valve": {ID: "valve", Name: "Valve"},
+		"lamp":  {ID: "lamp", Name: "Lamp"},
+	}
+
+	root := eliona.Asset{LocationalChildrenMap: make(map[string]eliona.Asset)}
+	buildAssetHierarchy(&root, spaces, assetsMap, &ontologyModel{}, appmodel.Configuration{})
+
+	plantRoom := root.LocationalChildrenMap["plant-room"]
+	assert.Len(t, plantRoom.LocationalChildrenMap, 1, "slaves are reached through their master")
+	ahu := plantRoom.LocationalChildrenMap["ahu"]
+	assert.Equal(t, int8(1), ahu.IsMaster)
+	var slaveIDs []string
+	for _, slave := range ahu.FunctionalChildrenSlice {
+		slaveIDs = append(slaveIDs, slave.ID)
+	}
+	assert.Equal(t, []string{"fan", "valve"}, slaveIDs)
+
+	office := root.LocationalChildrenMap["office"]
+	assert.Contains(t, office.LocationalChildrenMap, "lamp", "without a master, assets have no functional parent")
+	assert.Empty(t, office.LocationalChildrenMap["lamp"].FunctionalChildrenSlice)
+}
+
+func TestBuildAssetHierarchyKeepsSlavesOfFilteredMaster(t *testing.T) {
+	config := appmodel.Configuration{AssetFilter: [][]appmodel.FilterRule{{{Parameter: "name", Regex: "^(Plant room|Fan)$"}}}}
+	spaces := map[string]*ontologySpaceDTO{
+		"": {children: []ontologySpaceDTO{{
+			ID:     "plant-room",
+			Name:   "Plant room",
+			Assets: []ontologySpaceAssetDTO{{ID: "ahu", Master: true}, {ID: "fan"}},
+		}}},
+	}
+	spaces["plant-room"] = &spaces[""].children[0]
+	assetsMap := map[string]ontologyAssetDTO{
+		"ahu": {ID: "ahu", Name: "AHU"},
+		"fan": {ID: "fan", Name: "Fan"},
+	}
+
+	root := eliona.Asset{LocationalChildrenMap: make(map[string]eliona.Asset)}
+	buildAssetHierarchy(&root, spaces, assetsMap, &ontologyModel{}, config)
+
+	plantRoom := root.LocationalChildrenMap["plant-room"]
+	assert.NotContains(t, plantRoom.LocationalChildrenMap, "ahu")
+	assert.Contains(t, plantRoom.LocationalChildrenMap, "fan")
+}
+
+func TestConvertMapping(t *testing.T) {
+	enums := map[string]enumLabel{
+		"10": {"": "Building Protection"},
+		"2":  {"": "Standby"},
+		"0":  {"en": "Auto", "de": "Automatisch"},
+	}
+	for i := 0; i < 10; i++ { // Map iteration order is random.
+		assert.Equal(t, []map[string]any{
+			{"value": int64(0), "map": "Auto", "translation": map[string]string{"en": "Auto", "de": "Automatisch"}},
+			{"value": int64(2), "map": "Standby"},
+			{"value": int64(10), "map": "Building Protection"},
+		}, convertMapping(enums, FormatEnumeration))
+	}
+
+	assert.Equal(t, []map[string]any{
+		{"value": 0, "map": "Closed"},
+		{"value": 1, "map": "Open"},
+	}, convertMapping(map[string]enumLabel{"true": {"": "Open"}, "false": {"": "Closed"}}, FormatBoolean))
+
+	assert.Equal(t, []map[string]any{
+		{"value": "9", "map": "Nine"},
+		{"value": "10", "map": "Ten"},
+	}, convertMapping(map[string]enumLabel{"10": {"": "Ten"}, "9": {"": "Nine"}}, FormatString), "texts keep their keys, but are sorted like numbers")
+
+	assert.Nil(t, convertMapping(nil, FormatEnumeration))
+}
+
+func TestEnumLabelJSON(t *testing.T) {
+	var enums map[string]enumLabel
+	err := json.Unmarshal([]byte(`{"0": "Auto", "1": {"en": "Comfort", "de": "Komfort"}, "2": {"fr": "Veille"}}`), &enums)
+	assert.NoError(t, err)
+	assert.Equal(t, "Auto", enums["0"].text())
+	assert.Equal(t, "Comfort", enums["1"].text())
+	assert.Equal(t, "Veille", enums["2"].text(), "the first language should be used if there is no English text")
+
+	marshalled, err := json.Marshal(enums)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"0": "Auto", "1": {"en": "Comfort", "de": "Komfort"}, "2": {"fr": "Veille"}}`, string(marshalled))
+}
+
+func TestConvertAssetTemplateDefinesQualityOfProperties(t *testing.T) {
+	assetType := convertAssetTemplateToAssetType(assetTemplate{
+		ID:   "meter",
+		Name: "Meter",
+		Properties: []propertyTemplateInfo{
+			{ID: "serial", Name: "Serial number", Attributes: []templateAttributeInfo{{Name: "Serial number", Format: FormatString}}},
+		},
+	}, nil)
+
+	var names []string
+	for _, attribute := range assetType.Attributes {
+		names = append(names, attribute.Name)
+	}
+	assert.Contains(t, names, appmodel.QualityAttributeName("Serial number"), "live data of properties has a quality as well")
+}
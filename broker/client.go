@@ -16,9 +16,14 @@
 package broker
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	appmodel "open-bos/app/model"
+	"sort"
+	"time"
 
 	"github.com/eliona-smart-building-assistant/go-utils/common"
 	"github.com/eliona-smart-building-assistant/go-utils/log"
@@ -66,7 +71,68 @@ type ontologyDataTypeDTO struct {
 	Fields []ontologyDataTypeFieldDTO `json:"fields,omitempty"`
 	Min    *float64                   `json:"min,omitempty"`
 	Max    *float64                   `json:"max,omitempty"`
-	Enums  map[string]string          `json:"enums,omitempty"`
+	Enums  map[string]enumLabel       `json:"enums,omitempty"`
+}
+
+// enumLabel is the text of an enum value. OpenBOS sends either a plain text or
+// texts by language, e.g. {"en": "Comfort", "de": "Komfort"}. A plain text is
+// kept under the empty language.
+type enumLabel map[string]string
+
+func (l *enumLabel) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = enumLabel{"": text}
+		return nil
+	}
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return fmt.Errorf("enum label is neither a text nor texts by language: %v", err)
+	}
+	*l = texts
+	return nil
+}
+
+func (l enumLabel) MarshalJSON() ([]byte, error) {
+	if text, ok := l[""]; ok && len(l) == 1 {
+		return json.Marshal(text)
+	}
+	return json.Marshal(map[string]string(l))
+}
+
+// text returns the English text, or the plain text if there is none. Failing
+// both, the text of the first language is used.
+func (l enumLabel) text() string {
+	if text, ok := l["en"]; ok {
+		return text
+	}
+	if text, ok := l[""]; ok {
+		return text
+	}
+	languages := make([]string, 0, len(l))
+	for language := range l {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	if len(languages) == 0 {
+		return ""
+	}
+	return l[languages[0]]
+}
+
+// translation returns the texts by language, or nil if the label is a plain
+// text.
+func (l enumLabel) translation() map[string]string {
+	translation := make(map[string]string)
+	for language, text := range l {
+		if language != "" {
+			translation[language] = text
+		}
+	}
+	if len(translation) == 0 {
+		return nil
+	}
+	return translation
 }
 
 type dataTypeUncomplexified struct {
@@ -75,7 +141,7 @@ type dataTypeUncomplexified struct {
 	UnitID string
 	Min    *float64
 	Max    *float64
-	Enums  map[string]string
+	Enums  map[string]enumLabel
 }
 
 // unwrapComplexType recursively flattens complex datatypes into a slice of simple ones.
@@ -163,6 +229,17 @@ type ontologySpaceAssetDTO struct {
 	Master bool   `json:"master"`
 }
 
+// masterAssetID returns the ID of the master asset of the space, or an empty
+// string if there is none. Of several masters, the first one is used.
+func (s ontologySpaceDTO) masterAssetID() string {
+	for _, spaceAsset := range s.Assets {
+		if spaceAsset.Master {
+			return spaceAsset.ID
+		}
+	}
+	return ""
+}
+
 type ontologyDatapointDTO struct {
 	ID         string `json:"id"`
 	TemplateID string `json:"templateId"`
@@ -194,11 +271,11 @@ type ontologyUserDTO struct {
 }
 
 // getOntology retrieves the complete ontology of the edge.
-func (c *openBOSClient) getOntology() (*ontologyDTO, error) {
+func (c *openBOSClient) getOntology(ctx context.Context) (*ontologyDTO, error) {
 	endpoint := "core/application/data"
 
 	var ontology ontologyDTO
-	if err := c.doRequest("GET", endpoint, nil, nil, &ontology); err != nil {
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &ontology); err != nil {
 		return nil, err
 	}
 
@@ -206,11 +283,11 @@ func (c *openBOSClient) getOntology() (*ontologyDTO, error) {
 }
 
 // getOntologyVersion retrieves the current version of the edge data.
-func (c *openBOSClient) getOntologyVersion() (int32, error) {
+func (c *openBOSClient) getOntologyVersion(ctx context.Context) (int32, error) {
 	endpoint := "core/application/data/version"
 
 	var version int32
-	if err := c.doRequest("GET", endpoint, nil, nil, &version); err != nil {
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &version); err != nil {
 		return 0, err
 	}
 
@@ -230,15 +307,38 @@ type subscriptionCreateDTO struct {
 	DesiredUnits      []string `json:"desiredUnits,omitempty"`     // List of units you want for certain datapoints.
 }
 
+const (
+	// subscriptionLeaseTime is how long the edge keeps a live data or live
+	// alarm subscription whose webhook is unreachable.
+	subscriptionLeaseTime = 5 * time.Minute
+	// subscriptionMaxSendTime makes the edge send an empty event at least this
+	// often, so that a lost subscription can be noticed.
+	subscriptionMaxSendTime = 2 * time.Minute
+)
+
 type subscriptionResultDTO struct {
 	ID         *string `json:"id,omitempty"`
 	WebHookURL *string `json:"webhookURL,omitempty"`
 }
 
-func (c *openBOSClient) subscribeToOntologyChanges(configID int64) (*subscriptionResultDTO, error) {
+// subscriptionWebhookURL returns the webhook URL the edge calls for the given
+// subscription kind.
+func (c *openBOSClient) subscriptionWebhookURL(kind appmodel.SubscriptionKind) (string, error) {
+	switch kind {
+	case appmodel.SubscriptionKindOntology:
+		return url.JoinPath(c.webhookURL, "ontology-version")
+	case appmodel.SubscriptionKindData:
+		return url.JoinPath(c.webhookURL, "ontology-livedata")
+	case appmodel.SubscriptionKindAlarm:
+		return url.JoinPath(c.webhookURL, "ontology-livealarm")
+	}
+	return "", fmt.Errorf("unknown subscription kind %q", kind)
+}
+
+func (c *openBOSClient) subscribeToOntologyChanges(ctx context.Context) (*subscriptionResultDTO, error) {
 	endpoint := "core/application/data/version/subscribe"
 
-	webhookURL, err := url.JoinPath(c.webhookURL, fmt.Sprint(configID), "ontology-version")
+	webhookURL, err := c.subscriptionWebhookURL(appmodel.SubscriptionKindOntology)
 	if err != nil {
 		return nil, fmt.Errorf("joining URL for subscription: %v", err)
 	}
@@ -252,7 +352,7 @@ func (c *openBOSClient) subscribeToOntologyChanges(configID int64) (*subscriptio
 	}
 
 	var result subscriptionResultDTO
-	if err := c.doRequest("POST", endpoint, nil, sub, &result); err != nil {
+	if err := c.doRequest(ctx, "POST", endpoint, nil, sub, &result); err != nil {
 		return nil, fmt.Errorf("failed to subscribe to ontology changes: %v", err)
 	}
 
@@ -264,42 +364,51 @@ type subscriptionDeleteDTO struct {
 	ID         *string `json:"id,omitempty"`
 }
 
-func (c *openBOSClient) deleteOntologySubscription(del subscriptionDeleteDTO) error {
+func (c *openBOSClient) deleteOntologySubscription(ctx context.Context, del subscriptionDeleteDTO) error {
 	endpoint := "core/application/data/version/subscribe"
 
-	if err := c.doRequest("DELETE", endpoint, nil, del, nil); err != nil {
+	if err := c.doRequest(ctx, "DELETE", endpoint, nil, del, nil); err != nil {
 		return fmt.Errorf("failed to delete ontology subscription: %v", err)
 	}
 
 	return nil
 }
 
-func (c *openBOSClient) subscribeToDataChanges(configID int64) error {
+// subscribeToDataChanges subscribes to live data updates. Subscribing again
+// with the same webhook URL renews the lease. The edge converts the values of
+// datapoints to the desired units where possible.
+func (c *openBOSClient) subscribeToDataChanges(ctx context.Context, desiredUnits []string) (*subscriptionResultDTO, error) {
 	endpoint := "core/application/livedata/subscribe"
 
-	webhookURL, err := url.JoinPath(c.webhookURL, fmt.Sprint(configID), "ontology-livedata")
+	webhookURL, err := c.subscriptionWebhookURL(appmodel.SubscriptionKindData)
 	if err != nil {
-		return fmt.Errorf("joining URL for subscription: %v", err)
+		return nil, fmt.Errorf("joining URL for subscription: %v", err)
 	}
 
 	second := int32(1000)
-	minute := 60 * second
 	sub := subscriptionCreateDTO{
+		MaxSendTime:       int32(subscriptionMaxSendTime.Milliseconds()),
 		WebHookURL:        common.Ptr(webhookURL),
 		WebHookRetries:    3,
 		WebHookRetryDelay: 5 * second,
-		WebHookLeaseTime:  5 * minute,
+		WebHookLeaseTime:  int32(subscriptionLeaseTime.Milliseconds()),
 		WebhookPersist:    common.Ptr(true),
 		ContentType:       common.Ptr("application/json"),
-		// Info: There is also a parameter "desiredUnits" available. Implement if there is a use case.
+		DesiredUnits:      desiredUnits,
 	}
 
-	if err := c.doRequest("POST", endpoint, nil, sub, nil); err != nil {
-		return fmt.Errorf("failed to subscribe to data changes: %v", err)
+	var result subscriptionResultDTO
+	if err := c.doRequest(ctx, "POST", endpoint, nil, sub, &result); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to data changes: %v", err)
 	}
 
-	// After successful subscription, trigger the initial synchronization
-	refreshEndpoint := fmt.Sprintf("core/application/livedata/subscribe/refresh")
+	return &result, nil
+}
+
+// refreshDataSubscription makes the edge send the current values of all
+// datapoints to the webhook.
+func (c *openBOSClient) refreshDataSubscription(ctx context.Context, webhookURL string) error {
+	refreshEndpoint := "core/application/livedata/subscribe/refresh"
 
 	req := struct {
 		WebhookURL string `json:"webhookURL"`
@@ -307,17 +416,17 @@ func (c *openBOSClient) subscribeToDataChanges(configID int64) error {
 		WebhookURL: webhookURL,
 	}
 
-	if err := c.doRequest("PUT", refreshEndpoint, nil, req, nil); err != nil {
+	if err := c.doRequest(ctx, "PUT", refreshEndpoint, nil, req, nil); err != nil {
 		return fmt.Errorf("failed to trigger initial synchronization for webhookURL %s: %v", webhookURL, err)
 	}
 
 	return nil
 }
 
-func (c *openBOSClient) deleteDataSubscription(del subscriptionDeleteDTO) error {
+func (c *openBOSClient) deleteDataSubscription(ctx context.Context, del subscriptionDeleteDTO) error {
 	endpoint := "core/application/livedata/subscribe"
 
-	if err := c.doRequest("DELETE", endpoint, nil, del, nil); err != nil {
+	if err := c.doRequest(ctx, "DELETE", endpoint, nil, del, nil); err != nil {
 		return fmt.Errorf("failed to delete data subscription: %v", err)
 	}
 
@@ -350,7 +459,7 @@ type ontologyFullLiveAlarmDTO struct {
 }
 
 // getLiveAlarms retrieves live alarms since the given timestamp.
-func (c *openBOSClient) getLiveAlarms(timestamp string) ([]ontologyFullLiveAlarmDTO, error) {
+func (c *openBOSClient) getLiveAlarms(ctx context.Context, timestamp string) ([]ontologyFullLiveAlarmDTO, error) {
 	endpoint := "core/application/livealarm"
 
 	params := url.Values{}
@@ -359,7 +468,7 @@ func (c *openBOSClient) getLiveAlarms(timestamp string) ([]ontologyFullLiveAlarm
 	}
 
 	var alarms []ontologyFullLiveAlarmDTO
-	if err := c.doRequest("GET", endpoint, params, nil, &alarms); err != nil {
+	if err := c.doRequest(ctx, "GET", endpoint, params, nil, &alarms); err != nil {
 		return nil, err
 	}
 
@@ -373,10 +482,10 @@ type ontologyAlarmAckDTO struct {
 	Comment   string `json:"comment,omitempty"`
 }
 
-func (c *openBOSClient) ackAlarm(ack ontologyAlarmAckDTO) error {
+func (c *openBOSClient) ackAlarm(ctx context.Context, ack ontologyAlarmAckDTO) error {
 	endpoint := "core/application/livealarm/ack"
 
-	if err := c.doRequest("POST", endpoint, nil, ack, nil); err != nil {
+	if err := c.doRequest(ctx, "POST", endpoint, nil, ack, nil); err != nil {
 		return err
 	}
 
@@ -387,6 +496,7 @@ type datapointTemplateInfo struct {
 	ID         string
 	Name       string
 	Direction  string
+	Tags       []string
 	Attributes []templateAttributeInfo
 }
 
@@ -398,21 +508,29 @@ type propertyTemplateInfo struct {
 
 type templateAttributeInfo struct {
 	Name          string
+	Format        Format
 	DisplayUnitID *string
+	UnitID        string // Unit the edge sends the values in, only set for datapoints.
+	UnitSymbol    string
 	Min           *float64
 	Max           *float64
-	Enums         map[string]string
+	Enums         map[string]enumLabel
 }
 
 type assetTemplate struct {
 	ID         string
 	Name       string
 	Tags       []string
+	Icon       string
 	Properties []propertyTemplateInfo
 	Datapoints []datapointTemplateInfo
 }
 
-func (ontology ontologyDTO) getAssetTemplates(orphanDatapoints []ontologyDatapointDTO) []assetTemplate {
+// assetTemplates assembles the templates with their datapoints and properties
+// from the data types and units of the model. Datapoint units are replaced
+// according to the unit preferences and conversions of the configuration, as
+// the values are converted to them.
+func (m *ontologyModel) assetTemplates(ontology ontologyDTO, orphanDatapoints []ontologyDatapointDTO, unitPreferences map[string]string, unitConversions []appmodel.UnitConversion) []assetTemplate {
 	datapointTemplateMap := make(map[string][]ontologyDatapointTemplateDTO)
 	for _, dt := range ontology.DatapointTemplates {
 		switch {
@@ -433,6 +551,7 @@ func (ontology ontologyDTO) getAssetTemplates(orphanDatapoints []ontologyDatapoi
 	rootAsset := ontologyAssetOrSpaceTemplateDTO{
 		ID:   "root",
 		Name: "root",
+		Icon: "building",
 	}
 	ontology.SpaceTemplates = append(ontology.SpaceTemplates, rootAsset)
 	for _, orphanDatapoint := range orphanDatapoints {
@@ -459,37 +578,13 @@ func (ontology ontologyDTO) getAssetTemplates(orphanDatapoints []ontologyDatapoi
 		}
 	}
 
-	// dataTypeMap organizes datatypes in a map for simple lookup. Inside is a
-	// slice to support complex data types.
-	dataTypeMap := make(map[string][]dataTypeUncomplexified)
-	{
-		// dataTypeComplexMap is an intermediate step to map dataTypes for lookup,
-		// yet without unwrapping complex types.
-		dataTypeComplexMap := make(map[string]ontologyDataTypeDTO)
-		for _, dt := range ontology.DataTypes {
-			dataTypeComplexMap[dt.ID] = dt
-		}
-		for _, dt := range ontology.DataTypes {
-			name := dt.Name
-			if name == "" {
-				// Name might be null, in that case let's use ID as a fallback.
-				name = dt.ID
-			}
-			dataTypeMap[dt.ID] = dt.unwrapComplexType(dataTypeComplexMap, name)
-		}
-	}
-
-	unitMap := make(map[string]string)
-	for _, unit := range ontology.Units {
-		unitMap[unit.ID] = unit.Symbol
-	}
-
 	var assetTemplates []assetTemplate
 	for _, at := range append(ontology.AssetTemplates, ontology.SpaceTemplates...) {
 		assetTemplate := assetTemplate{
 			ID:         at.ID,
 			Name:       at.Name,
 			Tags:       at.Tags,
+			Icon:       at.Icon,
 			Datapoints: []datapointTemplateInfo{},
 			Properties: []propertyTemplateInfo{},
 		}
@@ -499,14 +594,20 @@ func (ontology ontologyDTO) getAssetTemplates(orphanDatapoints []ontologyDatapoi
 				ID:        datapointTemplate.ID,
 				Name:      datapointTemplate.Name,
 				Direction: datapointTemplate.Direction,
+				Tags:      datapointTemplate.Tags,
 			}
-			for _, dataType := range getDataTypes(datapointTemplate.TypeID, dataTypeMap) {
+			for _, dataType := range getDataTypes(datapointTemplate.TypeID, m.dataTypes) {
 				a := templateAttributeInfo{
-					Name:          dataType.Name,
-					Min:           dataType.Min,
-					Max:           dataType.Max,
-					Enums:         dataType.Enums,
-					DisplayUnitID: getDisplayUnitID(dataType, unitMap),
+					Name:   dataType.Name,
+					Format: ParseFormat(dataType.Format),
+					Min:    dataType.Min,
+					Max:    dataType.Max,
+					Enums:  dataType.Enums,
+				}
+				if a.Format != FormatString { // Texts are never converted.
+					a.DisplayUnitID = getDisplayUnitID(dataType, m.units, unitPreferences, unitConversions)
+					a.UnitID = dataType.UnitID
+					a.UnitSymbol = m.units[dataType.UnitID]
 				}
 				dataPoint.Attributes = append(dataPoint.Attributes, a)
 			}
@@ -518,13 +619,14 @@ func (ontology ontologyDTO) getAssetTemplates(orphanDatapoints []ontologyDatapoi
 				ID:   propertyTemplate.ID,
 				Name: propertyTemplate.Name,
 			}
-			for _, dataType := range getDataTypes(propertyTemplate.TypeID, dataTypeMap) {
+			for _, dataType := range getDataTypes(propertyTemplate.TypeID, m.dataTypes) {
 				a := templateAttributeInfo{
 					Name:          dataType.Name,
+					Format:        ParseFormat(dataType.Format),
 					Min:           dataType.Min,
 					Max:           dataType.Max,
 					Enums:         dataType.Enums,
-					DisplayUnitID: getDisplayUnitID(dataType, unitMap),
+					DisplayUnitID: getDisplayUnitID(dataType, m.units, nil, nil), // Properties are not converted.
 				}
 				property.Attributes = append(property.Attributes, a)
 			}
@@ -545,11 +647,26 @@ func getDataTypes(typeID string, dataTypeMap map[string][]dataTypeUncomplexified
 	return dataTypes
 }
 
-func getDisplayUnitID(dataType dataTypeUncomplexified, unitMap map[string]string) *string {
-	var unitSymbol string
+func getDisplayUnitID(dataType dataTypeUncomplexified, unitMap map[string]string, unitPreferences map[string]string, unitConversions []appmodel.UnitConversion) *string {
 	if dataType.UnitID == "" {
 		return nil
 	}
+	for _, conversion := range unitConversions {
+		if conversion.From != dataType.UnitID {
+			continue
+		}
+		if convertedSymbol, ok := unitMap[conversion.To]; ok {
+			return &convertedSymbol
+		}
+		// The edge might not know the unit it cannot convert to.
+		return &conversion.To
+	}
+	if preferredUnitID, ok := unitPreferences[dataType.UnitID]; ok {
+		if preferredSymbol, ok := unitMap[preferredUnitID]; ok {
+			return &preferredSymbol
+		}
+		log.Warn("client", "preferred unit %s for unit %s not found", preferredUnitID, dataType.UnitID)
+	}
 	unitSymbol, ok := unitMap[dataType.UnitID]
 	if !ok {
 		log.Warn("client", "unit %s not found", dataType.UnitID)
@@ -558,7 +675,7 @@ func getDisplayUnitID(dataType dataTypeUncomplexified, unitMap map[string]string
 	return &unitSymbol
 }
 
-func (c *openBOSClient) putData(attributesData []AttributeData) error {
+func (c *openBOSClient) putData(ctx context.Context, attributesData []AttributeData) error {
 	endpoint := "ontology/datapointinstance/livedata"
 
 	type livedata struct {
@@ -578,7 +695,7 @@ func (c *openBOSClient) putData(attributesData []AttributeData) error {
 		ErrorCode   string `json:"errorCode"`
 		InnerError  string `json:"innerError"`
 	}
-	if err := c.doRequest("POST", endpoint, nil, data, &result); err != nil {
+	if err := c.doRequest(ctx, "POST", endpoint, nil, data, &result); err != nil {
 		return fmt.Errorf("failed to put data: %v", err)
 	}
 
@@ -595,40 +712,65 @@ func (c *openBOSClient) putData(attributesData []AttributeData) error {
 	return nil
 }
 
-// subscribeToAlarmChanges subscribes to live alarm updates.
-func (c *openBOSClient) subscribeToAlarmChanges(configID int64) error {
+// subscribeToAlarmChanges subscribes to live alarm updates. Subscribing again
+// with the same webhook URL renews the lease.
+func (c *openBOSClient) subscribeToAlarmChanges(ctx context.Context) (*subscriptionResultDTO, error) {
 	endpoint := "core/application/livealarm/subscribe"
 
-	webhookURL, err := url.JoinPath(c.webhookURL, fmt.Sprint(configID), "ontology-livealarm")
+	webhookURL, err := c.subscriptionWebhookURL(appmodel.SubscriptionKindAlarm)
 	if err != nil {
-		return fmt.Errorf("joining URL for subscription: %v", err)
+		return nil, fmt.Errorf("joining URL for subscription: %v", err)
 	}
 
 	second := int32(1000)
-	minute := 60 * second
 	sub := subscriptionCreateDTO{
+		MaxSendTime:       int32(subscriptionMaxSendTime.Milliseconds()),
 		WebHookURL:        common.Ptr(webhookURL),
 		WebHookRetries:    3,
 		WebHookRetryDelay: 5 * second,
-		WebHookLeaseTime:  5 * minute,
+		WebHookLeaseTime:  int32(subscriptionLeaseTime.Milliseconds()),
 		WebhookPersist:    common.Ptr(true),
 		ContentType:       common.Ptr("application/json"),
 	}
 
-	if err := c.doRequest("POST", endpoint, nil, sub, nil); err != nil {
-		return fmt.Errorf("failed to subscribe to alarm changes: %v", err)
+	var result subscriptionResultDTO
+	if err := c.doRequest(ctx, "POST", endpoint, nil, sub, &result); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to alarm changes: %v", err)
 	}
 
-	return nil
+	return &result, nil
 }
 
 // deleteAlarmSubscription deletes the live alarm subscription.
-func (c *openBOSClient) deleteAlarmSubscription(del subscriptionDeleteDTO) error {
+func (c *openBOSClient) deleteAlarmSubscription(ctx context.Context, del subscriptionDeleteDTO) error {
 	endpoint := "core/application/livealarm/subscribe"
 
-	if err := c.doRequest("DELETE", endpoint, nil, del, nil); err != nil {
+	if err := c.doRequest(ctx, "DELETE", endpoint, nil, del, nil); err != nil {
 		return fmt.Errorf("failed to delete alarm subscription: %v", err)
 	}
 
 	return nil
 }
+
+// removeSubscription deletes the subscription by its ID if known, otherwise by
+// its webhook URL.
+func (c *openBOSClient) removeSubscription(ctx context.Context, subscription appmodel.Subscription) error {
+	del := subscriptionDeleteDTO{WebHookURL: common.Ptr(subscription.WebhookURL)}
+	if subscription.SubscriptionID != "" {
+		del.ID = common.Ptr(subscription.SubscriptionID)
+	}
+	return c.deleteSubscription(ctx, subscription.Kind, del)
+}
+
+// deleteSubscription deletes a subscription of any kind.
+func (c *openBOSClient) deleteSubscription(ctx context.Context, kind appmodel.SubscriptionKind, del subscriptionDeleteDTO) error {
+	switch kind {
+	case appmodel.SubscriptionKindOntology:
+		return c.deleteOntologySubscription(ctx, del)
+	case appmodel.SubscriptionKindData:
+		return c.deleteDataSubscription(ctx, del)
+	case appmodel.SubscriptionKindAlarm:
+		return c.deleteAlarmSubscription(ctx, del)
+	}
+	return fmt.Errorf("unknown subscription kind %q", kind)
+}
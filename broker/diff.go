@@ -0,0 +1,178 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+import (
+	appmodel "open-bos/app/model"
+	"reflect"
+	"slices"
+)
+
+// diffOntologies compares the current ontology with the previously synchronized
+// one. Templates are compared in their processed form, so that changes of data
+// types or units show up as changes of the templates that use them.
+func diffOntologies(previous, current ontologyDTO, previousTemplates, currentTemplates []assetTemplate) appmodel.OntologyDiff {
+	return appmodel.OntologyDiff{
+		Templates: diffEntities(
+			indexBy(previousTemplates, func(t assetTemplate) string { return t.ID }),
+			indexBy(currentTemplates, func(t assetTemplate) string { return t.ID }),
+			func(a, b assetTemplate) bool { return !reflect.DeepEqual(a, b) },
+			nil,
+		),
+		Assets: diffEntities(
+			assetStates(previous),
+			assetStates(current),
+			func(a, b assetState) bool {
+				return a.Name != b.Name || a.TemplateID != b.TemplateID || a.master != b.master || !slices.Equal(a.Tags, b.Tags)
+			},
+			func(a, b assetState) bool { return a.spaceID != b.spaceID || a.masterID != b.masterID },
+		),
+		Spaces: diffEntities(
+			indexBy(previous.Spaces, func(s ontologySpaceDTO) string { return s.ID }),
+			indexBy(current.Spaces, func(s ontologySpaceDTO) string { return s.ID }),
+			func(a, b ontologySpaceDTO) bool {
+				return a.Name != b.Name || a.TemplateID != b.TemplateID || !slices.Equal(a.Tags, b.Tags)
+			},
+			func(a, b ontologySpaceDTO) bool { return a.ParentID != b.ParentID },
+		),
+		Datapoints: diffEntities(
+			indexBy(previous.Datapoints, func(d ontologyDatapointDTO) string { return d.ID }),
+			indexBy(current.Datapoints, func(d ontologyDatapointDTO) string { return d.ID }),
+			func(a, b ontologyDatapointDTO) bool { return a.TemplateID != b.TemplateID },
+			func(a, b ontologyDatapointDTO) bool { return a.AssetID != b.AssetID || a.SpaceID != b.SpaceID },
+		),
+		Properties: diffEntities(
+			indexBy(previous.Properties, func(p ontologyPropertyDTO) string { return p.ID }),
+			indexBy(current.Properties, func(p ontologyPropertyDTO) string { return p.ID }),
+			func(a, b ontologyPropertyDTO) bool {
+				return a.TemplateID != b.TemplateID || !reflect.DeepEqual(a.Value, b.Value)
+			},
+			func(a, b ontologyPropertyDTO) bool { return a.AssetID != b.AssetID || a.SpaceID != b.SpaceID },
+		),
+	}
+}
+
+// assetState is an asset together with its placement, which is stored on the
+// space rather than on the asset itself.
+type assetState struct {
+	ontologyAssetDTO
+	spaceID  string
+	master   bool
+	masterID string // Master of the space, the functional parent of the asset. Empty for the master itself.
+}
+
+func assetStates(ontology ontologyDTO) map[string]assetState {
+	states := make(map[string]assetState, len(ontology.Assets))
+	for _, asset := range ontology.Assets {
+		states[asset.ID] = assetState{ontologyAssetDTO: asset}
+	}
+	for _, space := range ontology.Spaces {
+		masterID := space.masterAssetID()
+		for _, spaceAsset := range space.Assets {
+			if state, ok := states[spaceAsset.ID]; ok {
+				state.spaceID = space.ID
+				state.master = spaceAsset.Master
+				if spaceAsset.ID != masterID {
+					state.masterID = masterID
+				}
+				states[spaceAsset.ID] = state
+			}
+		}
+	}
+	return states
+}
+
+func indexBy[T any](items []T, id func(T) string) map[string]T {
+	index := make(map[string]T, len(items))
+	for _, item := range items {
+		index[id(item)] = item
+	}
+	return index
+}
+
+// diffEntities classifies entities by comparing them by ID. A nil moved
+// function means the entity cannot be moved.
+func diffEntities[T any](previous, current map[string]T, changed, moved func(a, b T) bool) appmodel.ChangeSet {
+	var changes appmodel.ChangeSet
+	for id, entity := range current {
+		old, ok := previous[id]
+		if !ok {
+			changes.Added = append(changes.Added, id)
+			continue
+		}
+		if changed(old, entity) {
+			changes.Changed = append(changes.Changed, id)
+		}
+		if moved != nil && moved(old, entity) {
+			changes.Moved = append(changes.Moved, id)
+		}
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	slices.Sort(changes.Added)
+	slices.Sort(changes.Changed)
+	slices.Sort(changes.Moved)
+	slices.Sort(changes.Removed)
+	return changes
+}
+
+// fullDiff reports all entities of the ontology as added.
+func fullDiff(current ontologyDTO, currentTemplates []assetTemplate) appmodel.OntologyDiff {
+	diff := diffOntologies(ontologyDTO{}, current, nil, currentTemplates)
+	diff.Full = true
+	return diff
+}
+
+// removedAttributes lists the attributes that are no longer part of templates
+// that still exist.
+func removedAttributes(previousTemplates, currentTemplates []assetTemplate) map[string][]string {
+	current := indexBy(currentTemplates, func(t assetTemplate) string { return t.ID })
+	removed := make(map[string][]string)
+	for _, previous := range previousTemplates {
+		template, ok := current[previous.ID]
+		if !ok {
+			continue
+		}
+		remaining := make(map[string]bool)
+		for _, name := range template.attributeNames() {
+			remaining[name] = true
+		}
+		for _, name := range previous.attributeNames() {
+			if !remaining[name] {
+				removed[previous.ID] = append(removed[previous.ID], name)
+			}
+		}
+	}
+	return removed
+}
+
+func (t assetTemplate) attributeNames() []string {
+	var names []string
+	for _, datapoint := range t.Datapoints {
+		for _, attribute := range datapoint.Attributes {
+			names = append(names, attribute.Name)
+		}
+	}
+	for _, property := range t.Properties {
+		for _, attribute := range property.Attributes {
+			names = append(names, attribute.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,107 @@
+package broker
+
+import (
+	"testing"
+
+	appmodel "open-bos/app/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOntologies(t *testing.T) {
+	previous := ontologyDTO{
+		Spaces: []ontologySpaceDTO{
+			{ID: "building", Name: "Building", TemplateID: "building"},
+			{ID: "floor1", Name: "Floor 1", ParentID: "building", TemplateID: "floor", Assets: []ontologySpaceAssetDTO{{ID: "lamp"}, {ID: "blind"}}},
+			{ID: "floor2", Name: "Floor 2", ParentID: "building", TemplateID: "floor"},
+		},
+		Assets: []ontologyAssetDTO{
+			{ID: "lamp", Name: "Lamp", TemplateID: "light"},
+			{ID: "blind", Name: "Blind", TemplateID: "blind"},
+			{ID: "heater", Name: "Heater", TemplateID: "heater"},
+		},
+		Datapoints: []ontologyDatapointDTO{
+			{ID: "lamp-switch", TemplateID: "switch", AssetID: "lamp"},
+			{ID: "blind-position", TemplateID: "position", AssetID: "blind"},
+		},
+		Properties: []ontologyPropertyDTO{
+			{ID: "lamp-power", TemplateID: "power", AssetID: "lamp", Value: 40.0},
+		},
+	}
+	current := ontologyDTO{
+		Spaces: []ontologySpaceDTO{
+			{ID: "building", Name: "Building", TemplateID: "building"},
+			{ID: "floor1", Name: "Ground floor", ParentID: "building", TemplateID: "floor", Assets: []ontologySpaceAssetDTO{{ID: "lamp"}}},
+			{ID: "floor2", Name: "Floor 2", ParentID: "building", TemplateID: "floor", Assets: []ontologySpaceAssetDTO{{ID: "blind"}}},
+		},
+		Assets: []ontologyAssetDTO{
+			{ID: "lamp", Name: "Lamp", TemplateID: "light"},
+			{ID: "blind", Name: "Blind", TemplateID: "blind"},
+			{ID: "fan", Name: "Fan", TemplateID: "fan"},
+		},
+		Datapoints: []ontologyDatapointDTO{
+			{ID: "lamp-switch", TemplateID: "dimmer", AssetID: "lamp"},
+			{ID: "blind-position", TemplateID: "position", AssetID: "blind"},
+			{ID: "fan-speed", TemplateID: "speed", AssetID: "fan"},
+		},
+		Properties: []ontologyPropertyDTO{
+			{ID: "lamp-power", TemplateID: "power", AssetID: "lamp", Value: 60.0},
+		},
+	}
+	previousTemplates := []assetTemplate{{ID: "light", Name: "Light"}, {ID: "heater", Name: "Heater"}}
+	currentTemplates := []assetTemplate{{ID: "light", Name: "Luminaire"}, {ID: "fan", Name: "Fan"}}
+
+	diff := diffOntologies(previous, current, previousTemplates, currentTemplates)
+
+	assert.False(t, diff.Full)
+	assert.Equal(t, appmodel.ChangeSet{Added: []string{"fan"}, Changed: []string{"light"}, Removed: []string{"heater"}}, diff.Templates)
+	assert.Equal(t, appmodel.ChangeSet{Added: []string{"fan"}, Moved: []string{"blind"}, Removed: []string{"heater"}}, diff.Assets)
+	assert.Equal(t, appmodel.ChangeSet{Changed: []string{"floor1"}}, diff.Spaces)
+	assert.Equal(t, appmodel.ChangeSet{Added: []string{"fan-speed"}, Changed: []string{"lamp-switch"}}, diff.Datapoints)
+	assert.Equal(t, appmodel.ChangeSet{Changed: []string{"lamp-power"}}, diff.Properties)
+}
+
+func TestDiffOntologiesWithoutChanges(t *testing.T) {
+	ontology := ontologyDTO{
+		Spaces:     []ontologySpaceDTO{{ID: "building", Name: "Building", Assets: []ontologySpaceAssetDTO{{ID: "lamp", Master: true}}}},
+		Assets:     []ontologyAssetDTO{{ID: "lamp", Name: "Lamp", TemplateID: "light"}},
+		Properties: []ontologyPropertyDTO{{ID: "lamp-power", TemplateID: "power", AssetID: "lamp", Value: map[string]any{"watts": 40.0}}},
+	}
+	templates := []assetTemplate{{ID: "light", Name: "Light"}}
+
+	diff := diffOntologies(ontology, ontology, templates, templates)
+	assert.True(t, diff.IsEmpty())
+
+	full := fullDiff(ontology, templates)
+	assert.True(t, full.Full)
+	assert.Equal(t, []string{"lamp"}, full.Assets.Added)
+	assert.Equal(t, []string{"light"}, full.Templates.Added)
+}
+
+func TestRemovedAttributes(t *testing.T) {
+	previous := []assetTemplate{
+		{ID: "light", Datapoints: []datapointTemplateInfo{{ID: "switch", Attributes: []templateAttributeInfo{{Name: "switch"}, {Name: "dim"}}}}},
+		{ID: "heater", Properties: []propertyTemplateInfo{{ID: "power", Attributes: []templateAttributeInfo{{Name: "power"}}}}},
+	}
+	current := []assetTemplate{
+		{ID: "light", Datapoints: []datapointTemplateInfo{{ID: "switch", Attributes: []templateAttributeInfo{{Name: "switch"}, {Name: "color"}}}}},
+	}
+
+	assert.Equal(t, map[string][]string{"light": {"dim"}}, removedAttributes(previous, current))
+}
+
+func TestDiffOntologiesMovesSlavesOfNewMaster(t *testing.T) {
+	previous := ontologyDTO{
+		Spaces: []ontologySpaceDTO{{ID: "plant-room", Name: "Plant room", Assets: []ontologySpaceAssetDTO{{ID: "ahu", Master: true}, {ID: "fan"}, {ID: "pump"}}}},
+		Assets: []ontologyAssetDTO{{ID: "ahu", Name: "AHU"}, {ID: "fan", Name: "Fan"}, {ID: "pump", Name: "Pump"}},
+	}
+	current := ontologyDTO{
+		Spaces: []ontologySpaceDTO{{ID: "plant-room", Name: "Plant room", Assets: []ontologySpaceAssetDTO{{ID: "ahu"}, {ID: "fan"}, {ID: "pump", Master: true}}}},
+		Assets: previous.Assets,
+	}
+
+	diff := diffOntologies(previous, current, nil, nil)
+
+	assert.Equal(t, []string{"ahu", "pump"}, diff.Assets.Changed)
+	assert.Equal(t, []string{"ahu", "fan", "pump"}, diff.Assets.Moved)
+}
@@ -0,0 +1,247 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	appmodel "open-bos/app/model"
+
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+// The values below are sent by OpenBOS as free-form strings. They are parsed
+// ignoring case and separators, so that e.g. "Good", "good" and "GOOD" are all
+// recognized. Anything else becomes the unknown variant of the enum and is
+// counted, see UnknownValues.
+
+// Quality of a value reported by OpenBOS.
+type Quality string
+
+const (
+	QualityUnknown   Quality = "unknown"
+	QualityGood      Quality = "good"
+	QualityUncertain Quality = "uncertain" // e.g. "uncertainLastUsable"
+	QualityBad       Quality = "bad"       // e.g. "badNotConnected"
+)
+
+// ParseQuality parses the quality of a value or an alarm. An empty quality
+// (alarms do not always have one) is unknown, but not counted as such.
+func ParseQuality(quality string) Quality {
+	normalized := normalizeEnumValue(quality)
+	switch {
+	case normalized == "":
+		return QualityUnknown
+	case normalized == string(QualityGood):
+		return QualityGood
+	case strings.HasPrefix(normalized, string(QualityUncertain)):
+		return QualityUncertain
+	case strings.HasPrefix(normalized, string(QualityBad)):
+		return QualityBad
+	}
+	countUnknownValue("quality", quality)
+	return QualityUnknown
+}
+
+// AttributeValue returns the value of the quality attribute in Eliona. Unknown
+// qualities are shown as bad, as the value cannot be trusted.
+func (q Quality) AttributeValue() int {
+	switch q {
+	case QualityGood:
+		return appmodel.QualityGood
+	case QualityUncertain:
+		return appmodel.QualityUncertain
+	default:
+		return appmodel.QualityBad
+	}
+}
+
+// Direction of a datapoint.
+type Direction string
+
+const (
+	DirectionUnknown            Direction = "unknown"
+	DirectionFeedback           Direction = "Feedback"
+	DirectionCommand            Direction = "Command"
+	DirectionCommandAndFeedback Direction = "CommandAndFeedback"
+)
+
+func ParseDirection(direction string) Direction {
+	return parseEnum("direction", direction, DirectionUnknown, []Direction{
+		DirectionFeedback,
+		DirectionCommand,
+		DirectionCommandAndFeedback,
+	})
+}
+
+// Severity of an alarm.
+type Severity string
+
+const (
+	SeverityUnknown  Severity = "unknown"
+	SeverityLog      Severity = "Log"
+	SeverityLow      Severity = "Low"
+	SeverityHigh     Severity = "High"
+	SeverityUrgent   Severity = "Urgent"
+	SeverityCritical Severity = "Critical"
+)
+
+func ParseSeverity(severity string) Severity {
+	return parseEnum("severity", severity, SeverityUnknown, []Severity{
+		SeverityLog,
+		SeverityLow,
+		SeverityHigh,
+		SeverityUrgent,
+		SeverityCritical,
+	})
+}
+
+// Trigger type of an alarm.
+type Trigger string
+
+const (
+	TriggerUnknown        Trigger = "unknown"
+	TriggerAnalogNotValue Trigger = "analognotvalue"
+	TriggerAnalogValue    Trigger = "analogvalue"
+	TriggerDigitalOff     Trigger = "digitaloff"
+	TriggerDigitalOn      Trigger = "digitalon"
+	TriggerAnalogOutBand2 Trigger = "analogoutband2"
+	TriggerAnalogOutBand1 Trigger = "analogoutband1"
+	TriggerAnalogInBand2  Trigger = "analoginband2"
+	TriggerAnalogInBand1  Trigger = "analoginband1"
+	TriggerAnalogLo       Trigger = "analoglo"
+	TriggerAnalogLoLo     Trigger = "analoglolo"
+	TriggerAnalogHi       Trigger = "analoghi"
+	TriggerAnalogHiHi     Trigger = "analoghihi"
+	TriggerNetworkError   Trigger = "networkerror"
+)
+
+func ParseTrigger(trigger string) Trigger {
+	return parseEnum("trigger", trigger, TriggerUnknown, []Trigger{
+		TriggerAnalogNotValue,
+		TriggerAnalogValue,
+		TriggerDigitalOff,
+		TriggerDigitalOn,
+		TriggerAnalogOutBand2,
+		TriggerAnalogOutBand1,
+		TriggerAnalogInBand2,
+		TriggerAnalogInBand1,
+		TriggerAnalogLo,
+		TriggerAnalogLoLo,
+		TriggerAnalogHi,
+		TriggerAnalogHiHi,
+		TriggerNetworkError,
+	})
+}
+
+// parseEnum returns the known value matching value, or unknown. Empty values
+// are not counted, as most of these fields are nullable.
+func parseEnum[T ~string](enum string, value string, unknown T, known []T) T {
+	normalized := normalizeEnumValue(value)
+	if normalized == "" {
+		return unknown
+	}
+	for _, k := range known {
+		if normalizeEnumValue(string(k)) == normalized {
+			return k
+		}
+	}
+	countUnknownValue(enum, value)
+	return unknown
+}
+
+// normalizeEnumValue lowercases the value and removes everything but letters
+// and digits, e.g. "Command_And_Feedback" becomes "commandandfeedback".
+func normalizeEnumValue(value string) string {
+	return appmodel.NormalizeIdentifier(value)
+}
+
+// maxUnknownValuesPerEnum limits how many distinct unknown values are counted
+// per enum. Any further ones are counted together.
+const maxUnknownValuesPerEnum = 50
+
+// UnknownValue is a value OpenBOS sent that could not be parsed.
+type UnknownValue struct {
+	Enum   string
+	Value  string
+	Others bool // Counts all values beyond maxUnknownValuesPerEnum together.
+	Count  int64
+}
+
+var unknownValues = struct {
+	sync.Mutex
+	counts   map[UnknownValue]int64 // Keyed without the count.
+	distinct map[string]int         // Number of values counted on their own, by enum.
+}{counts: make(map[UnknownValue]int64), distinct: make(map[string]int)}
+
+func countUnknownValue(enum string, value string) {
+	recordUnknownValue(enum, value, false)
+}
+
+// reportUnknownValue records an unknown value only the first time. It is meant
+// for values that are looked up again and again, rather than received.
+func reportUnknownValue(enum string, value string) {
+	recordUnknownValue(enum, value, true)
+}
+
+func recordUnknownValue(enum string, value string, once bool) {
+	key := UnknownValue{Enum: enum, Value: value}
+	unknownValues.Lock()
+	_, seen := unknownValues.counts[key]
+	if seen && once {
+		unknownValues.Unlock()
+		return
+	}
+	if !seen {
+		if unknownValues.distinct[enum] >= maxUnknownValuesPerEnum {
+			key = UnknownValue{Enum: enum, Value: "other values", Others: true}
+		} else {
+			unknownValues.distinct[enum]++
+		}
+	}
+	unknownValues.counts[key]++
+	first := unknownValues.counts[key] == 1
+	unknownValues.Unlock()
+	if first && key.Others {
+		log.Warn("broker", "Received more than %d unknown %s values from OpenBOS, counting further ones together", maxUnknownValuesPerEnum, enum)
+	} else if first {
+		log.Warn("broker", "Received unknown %s %q from OpenBOS", enum, value)
+	}
+}
+
+// UnknownValues returns how often each unknown value was received, sorted by
+// enum and value. The values counted together come last for each enum.
+func UnknownValues() []UnknownValue {
+	unknownValues.Lock()
+	defer unknownValues.Unlock()
+	values := make([]UnknownValue, 0, len(unknownValues.counts))
+	for key, count := range unknownValues.counts {
+		key.Count = count
+		values = append(values, key)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Enum != values[j].Enum {
+			return values[i].Enum < values[j].Enum
+		}
+		if values[i].Others != values[j].Others {
+			return values[j].Others
+		}
+		return values[i].Value < values[j].Value
+	})
+	return values
+}
@@ -0,0 +1,81 @@
+package broker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuality(t *testing.T) {
+	tests := map[string]Quality{
+		"good":                QualityGood,
+		"Good":                QualityGood,
+		" GOOD ":              QualityGood,
+		"uncertainLastUsable": QualityUncertain,
+		"UncertainSensorCal":  QualityUncertain,
+		"badNotConnected":     QualityBad,
+		"Bad":                 QualityBad,
+		"":                    QualityUnknown,
+		"great":               QualityUnknown,
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, ParseQuality(input), "input %q", input)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	assert.Equal(t, DirectionFeedback, ParseDirection("feedback"))
+	assert.Equal(t, DirectionCommand, ParseDirection("COMMAND"))
+	assert.Equal(t, DirectionCommandAndFeedback, ParseDirection("commandAndFeedback"))
+	assert.Equal(t, DirectionCommandAndFeedback, ParseDirection("command_and_feedback"))
+	assert.Equal(t, DirectionUnknown, ParseDirection("sideways"))
+}
+
+func TestParseSeverityAndTrigger(t *testing.T) {
+	assert.Equal(t, SeverityCritical, ParseSeverity("critical"))
+	assert.Equal(t, SeverityLog, ParseSeverity("Log"))
+	assert.Equal(t, SeverityUnknown, ParseSeverity(""))
+	assert.Equal(t, TriggerAnalogNotValue, ParseTrigger("Analognotvalue"))
+	assert.Equal(t, TriggerAnalogHiHi, ParseTrigger("analogHiHi"))
+	assert.Equal(t, TriggerUnknown, ParseTrigger("analogmedium"))
+}
+
+func TestUnknownValuesAreCounted(t *testing.T) {
+	ParseSeverity("Apocalyptic")
+	ParseSeverity("Apocalyptic")
+	ParseSeverity("") // Empty values are not counted.
+
+	var found bool
+	for _, value := range UnknownValues() {
+		assert.NotEmpty(t, value.Value)
+		if value.Enum == "severity" && value.Value == "Apocalyptic" {
+			found = true
+			assert.Equal(t, int64(2), value.Count)
+		}
+	}
+	assert.True(t, found, "unknown severity should be counted")
+}
+
+func TestDetermineSubtypeIgnoresCase(t *testing.T) {
+	assert.Equal(t, "input", string(determineSubtype(ParseDirection("FEEDBACK"))))
+	assert.Equal(t, "output", string(determineSubtype(ParseDirection("Command"))))
+	assert.Equal(t, "info", string(determineSubtype(ParseDirection("unexpected"))))
+}
+
+func TestUnknownValuesAreCappedPerEnum(t *testing.T) {
+	for i := 0; i < maxUnknownValuesPerEnum+5; i++ {
+		countUnknownValue("test", fmt.Sprintf("value-%03d", i))
+	}
+	countUnknownValue("test", "value-000")
+
+	var values []UnknownValue
+	for _, value := range UnknownValues() {
+		if value.Enum == "test" {
+			values = append(values, value)
+		}
+	}
+	assert.Len(t, values, maxUnknownValuesPerEnum+1)
+	assert.Equal(t, UnknownValue{Enum: "test", Value: "value-000", Count: 2}, values[0])
+	assert.Equal(t, UnknownValue{Enum: "test", Value: "other values", Others: true, Count: 5}, values[len(values)-1])
+}
@@ -0,0 +1,218 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	appmodel "open-bos/app/model"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/eliona-smart-building-assistant/go-utils/common"
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+// Format of the values of a primitive OpenBOS data type.
+type Format string
+
+const (
+	FormatUnknown     Format = "unknown"
+	FormatBoolean     Format = "boolean"
+	FormatInteger     Format = "integer"
+	FormatFloat       Format = "float"
+	FormatString      Format = "string"
+	FormatEnumeration Format = "enumeration"
+)
+
+// floatPrecision is the number of decimal places Eliona shows for floats.
+const floatPrecision = 2
+
+// ParseFormat parses the format of a data type. Formats are grouped by how
+// Eliona handles their values, e.g. "int32" and "long" are both integers.
+func ParseFormat(format string) Format {
+	switch normalizeEnumValue(format) {
+	case "":
+		return FormatUnknown
+	case "boolean", "bool":
+		return FormatBoolean
+	case "integer", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "long", "short", "byte":
+		return FormatInteger
+	case "float", "double", "decimal", "number", "real", "single":
+		return FormatFloat
+	case "string", "text":
+		return FormatString
+	case "enumeration", "enum":
+		return FormatEnumeration
+	}
+	countUnknownValue("format", format)
+	return FormatUnknown
+}
+
+// applyFormat sets the attribute metadata that depends on the format of the
+// values.
+func applyFormat(attribute *api.AssetTypeAttribute, format Format) {
+	switch format {
+	case FormatBoolean:
+		attribute.IsDigital = *api.NewNullableBool(common.Ptr(true))
+		attribute.Precision = *api.NewNullableInt64(common.Ptr(int64(0)))
+		attribute.Min = *api.NewNullableFloat64(common.Ptr(0.0))
+		attribute.Max = *api.NewNullableFloat64(common.Ptr(1.0))
+	case FormatInteger, FormatEnumeration:
+		attribute.Precision = *api.NewNullableInt64(common.Ptr(int64(0)))
+	case FormatFloat:
+		attribute.Precision = *api.NewNullableInt64(common.Ptr(int64(floatPrecision)))
+	case FormatString:
+		// Texts have no numeric semantics.
+		attribute.Unit.Unset()
+		attribute.Min.Unset()
+		attribute.Max.Unset()
+	}
+}
+
+// CoerceToEliona converts the values of the attributes in data to the type
+// Eliona expects for their format, e.g. booleans to 0 and 1. Values that
+// cannot be converted are removed from data.
+func CoerceToEliona(attributes []appmodel.Attribute, data map[string]any) {
+	for _, attribute := range attributes {
+		value, ok := data[attribute.Name]
+		if !ok || value == nil {
+			continue
+		}
+		coerced, err := toElionaValue(Format(attribute.Format), value)
+		if err != nil {
+			log.Warn("broker", "dropping value of attribute %s: %v", attribute.Name, err)
+			delete(data, attribute.Name)
+			continue
+		}
+		data[attribute.Name] = coerced
+	}
+}
+
+// CoerceToOpenBOS converts a value of the attribute to the type OpenBOS
+// expects for its format, e.g. 0 and 1 to booleans.
+func CoerceToOpenBOS(attribute appmodel.Attribute, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch Format(attribute.Format) {
+	case FormatBoolean:
+		return toBool(value)
+	case FormatInteger:
+		number, err := toNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return int64(math.Round(number)), nil
+	case FormatFloat:
+		return toNumber(value)
+	case FormatString:
+		return toText(value), nil
+	}
+	return value, nil
+}
+
+func toElionaValue(format Format, value any) (any, error) {
+	switch format {
+	case FormatBoolean:
+		b, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case FormatInteger:
+		number, err := toNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return int64(math.Round(number)), nil
+	case FormatFloat:
+		return toNumber(value)
+	case FormatString:
+		return toText(value), nil
+	case FormatEnumeration:
+		// Enum states are mostly numbers, but might be sent as text.
+		if number, err := toNumber(value); err == nil {
+			if number == math.Trunc(number) {
+				return int64(number), nil
+			}
+			return number, nil
+		}
+	}
+	return value, nil
+}
+
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "on", "1":
+			return true, nil
+		case "false", "off", "0":
+			return false, nil
+		}
+		return false, fmt.Errorf("%q is not a boolean", v)
+	}
+	number, err := toNumber(value)
+	if err != nil {
+		return false, err
+	}
+	return number != 0, nil
+}
+
+func toNumber(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return number, nil
+	}
+	return 0, fmt.Errorf("%v (%T) is not a number", value, value)
+}
+
+func toText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
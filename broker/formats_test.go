@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"testing"
+
+	appmodel "open-bos/app/model"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/eliona-smart-building-assistant/go-utils/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	assert.Equal(t, FormatBoolean, ParseFormat("Boolean"))
+	assert.Equal(t, FormatInteger, ParseFormat("int32"))
+	assert.Equal(t, FormatInteger, ParseFormat("UInt16"))
+	assert.Equal(t, FormatFloat, ParseFormat("double"))
+	assert.Equal(t, FormatString, ParseFormat("string"))
+	assert.Equal(t, FormatEnumeration, ParseFormat("enumeration"))
+	assert.Equal(t, FormatUnknown, ParseFormat(""))
+	assert.Equal(t, FormatUnknown, ParseFormat("quaternion"))
+}
+
+func TestApplyFormat(t *testing.T) {
+	boolean := api.AssetTypeAttribute{Name: "On"}
+	applyFormat(&boolean, FormatBoolean)
+	assert.True(t, boolean.GetIsDigital())
+	assert.Equal(t, int64(0), boolean.GetPrecision())
+
+	integer := api.AssetTypeAttribute{Name: "Count"}
+	applyFormat(&integer, FormatInteger)
+	assert.Equal(t, int64(0), integer.GetPrecision())
+	assert.False(t, integer.IsDigital.IsSet())
+
+	text := api.AssetTypeAttribute{
+		Name: "Label",
+		Unit: *api.NewNullableString(common.Ptr("°C")),
+		Min:  *api.NewNullableFloat64(common.Ptr(0.0)),
+	}
+	applyFormat(&text, FormatString)
+	assert.False(t, text.Unit.IsSet())
+	assert.False(t, text.Min.IsSet())
+	assert.False(t, text.Precision.IsSet())
+}
+
+func TestCoerceToEliona(t *testing.T) {
+	attributes := []appmodel.Attribute{
+		{Name: "on", Format: string(FormatBoolean)},
+		{Name: "count", Format: string(FormatInteger)},
+		{Name: "temperature", Format: string(FormatFloat)},
+		{Name: "label", Format: string(FormatString)},
+		{Name: "state", Format: string(FormatEnumeration)},
+		{Name: "broken", Format: string(FormatFloat)},
+		{Name: "legacy"},
+	}
+	data := map[string]any{
+		"on":          true,
+		"count":       "41.6",
+		"temperature": "21.5",
+		"label":       12.5,
+		"state":       "2",
+		"broken":      "n/a",
+		"legacy":      "as is",
+	}
+
+	CoerceToEliona(attributes, data)
+
+	assert.Equal(t, map[string]any{
+		"on":          1,
+		"count":       int64(42),
+		"temperature": 21.5,
+		"label":       "12.5",
+		"state":       int64(2),
+		"legacy":      "as is",
+	}, data, "values that cannot be coerced should be dropped")
+}
+
+func TestCoerceToOpenBOS(t *testing.T) {
+	value, err := CoerceToOpenBOS(appmodel.Attribute{Format: string(FormatBoolean)}, 1.0)
+	assert.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	value, err = CoerceToOpenBOS(appmodel.Attribute{Format: string(FormatInteger)}, 2.7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), value)
+
+	value, err = CoerceToOpenBOS(appmodel.Attribute{Format: string(FormatString)}, 5.0)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", value)
+
+	_, err = CoerceToOpenBOS(appmodel.Attribute{Format: string(FormatBoolean)}, "maybe")
+	assert.Error(t, err)
+}
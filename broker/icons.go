@@ -0,0 +1,169 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+// elionaIcons maps OpenBOS icon identifiers to the icons Eliona offers for
+// asset types. Identifiers are compared ignoring case and separators, see
+// normalizeEnumValue. Configurations can override and extend the table.
+var elionaIcons = map[string]string{
+	"building": "building",
+	"site":     "building",
+	"campus":   "building",
+
+	"floor":  "storey",
+	"storey": "storey",
+	"level":  "storey",
+
+	"room":        "people",
+	"zone":        "people",
+	"area":        "people",
+	"office":      "people",
+	"meetingroom": "people",
+	"occupancy":   "people",
+	"presence":    "people",
+	"people":      "people",
+
+	"ahu":               "ventilation",
+	"airhandlingunit":   "ventilation",
+	"fan":               "ventilation",
+	"fancoil":           "ventilation",
+	"fancoilunit":       "ventilation",
+	"fcu":               "ventilation",
+	"vav":               "ventilation",
+	"damper":            "ventilation",
+	"hvac":              "ventilation",
+	"ventilation":       "ventilation",
+	"airconditioning":   "ventilation",
+	"airconditioner":    "ventilation",
+	"heatpump":          "ventilation",
+	"chiller":           "ventilation",
+	"coolingtower":      "ventilation",
+	"rooftopunit":       "ventilation",
+	"rtu":               "ventilation",
+	"exhaustfan":        "ventilation",
+	"supplyfan":         "ventilation",
+	"returnfan":         "ventilation",
+	"variableairvolume": "ventilation",
+
+	"light":     "light",
+	"lighting":  "light",
+	"lamp":      "light",
+	"luminaire": "light",
+	"dimmer":    "light",
+
+	"blind":    "blind",
+	"blinds":   "blind",
+	"shade":    "blind",
+	"shutter":  "blind",
+	"sunblind": "blind",
+	"awning":   "blind",
+
+	"door":   "closable",
+	"window": "closable",
+	"gate":   "closable",
+	"lock":   "closable",
+
+	"elevator":  "elevator",
+	"lift":      "elevator",
+	"escalator": "elevator",
+
+	"meter":           "power",
+	"energymeter":     "power",
+	"electricmeter":   "power",
+	"electricity":     "power",
+	"power":           "power",
+	"powermeter":      "power",
+	"switchboard":     "power",
+	"pv":              "power",
+	"solar":           "power",
+	"battery":         "power",
+	"charger":         "power",
+	"evcharger":       "power",
+	"chargingstation": "power",
+
+	"water":      "water",
+	"watermeter": "water",
+	"pump":       "water",
+	"valve":      "water",
+	"boiler":     "water",
+	"heating":    "water",
+	"radiator":   "water",
+	"sprinkler":  "water",
+
+	"sensor":         "environment",
+	"temperature":    "environment",
+	"thermostat":     "environment",
+	"humidity":       "environment",
+	"airquality":     "environment",
+	"co2":            "environment",
+	"environment":    "environment",
+	"roomcontroller": "environment",
+
+	"weather":        "weather",
+	"weatherstation": "weather",
+
+	"parking":    "parking",
+	"parkinglot": "parking",
+	"carpark":    "parking",
+	"garage":     "parking",
+
+	"gateway":    "gateway",
+	"controller": "gateway",
+	"plc":        "gateway",
+	"edge":       "gateway",
+	"router":     "gateway",
+
+	"rack":       "rack",
+	"server":     "rack",
+	"serverrack": "rack",
+	"cabinet":    "rack",
+
+	"button":     "button",
+	"pushbutton": "button",
+	"switch":     "button",
+
+	"trash": "trash",
+	"bin":   "trash",
+	"waste": "trash",
+
+	"tank":    "filling",
+	"silo":    "filling",
+	"filling": "filling",
+
+	"mailbox": "mailbox",
+
+	"vibration": "vibration",
+}
+
+// elionaIcon returns the Eliona icon of an OpenBOS icon identifier, looking
+// at the overrides of the configuration first. The overrides are keyed by
+// normalized icons, see appmodel.NormalizeIconMapping. Templates without an
+// icon get the default icon, unmapped icons are reported once, see
+// UnknownValues.
+func elionaIcon(icon string, overrides map[string]string) (string, bool) {
+	normalized := normalizeEnumValue(icon)
+	if normalized == "" {
+		return "", false
+	}
+	if elionaIcon, ok := overrides[normalized]; ok {
+		return elionaIcon, true
+	}
+	if elionaIcon, ok := elionaIcons[normalized]; ok {
+		return elionaIcon, true
+	}
+	reportUnknownValue("icon", icon)
+	return "", false
+}
@@ -0,0 +1,58 @@
+package broker
+
+import (
+	"slices"
+	"testing"
+
+	appmodel "open-bos/app/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElionaIcon(t *testing.T) {
+	overrides := appmodel.NormalizeIconMapping(map[string]string{"Chiller": "environment", "evse": "power"})
+
+	icon, ok := elionaIcon("Air_Handling_Unit", overrides)
+	assert.True(t, ok)
+	assert.Equal(t, "ventilation", icon)
+
+	icon, ok = elionaIcon("chiller", overrides)
+	assert.True(t, ok)
+	assert.Equal(t, "environment", icon, "overrides win over the built-in mapping")
+
+	icon, ok = elionaIcon("EVSE", overrides)
+	assert.True(t, ok)
+	assert.Equal(t, "power", icon, "overrides extend the built-in mapping")
+
+	_, ok = elionaIcon("", overrides)
+	assert.False(t, ok)
+
+	_, ok = elionaIcon("unicorn", nil)
+	assert.False(t, ok)
+	assert.True(t, slices.ContainsFunc(UnknownValues(), func(v UnknownValue) bool {
+		return v.Enum == "icon" && v.Value == "unicorn"
+	}), "unmapped icons should be reported")
+}
+
+func TestBuiltInIconsAreElionaIcons(t *testing.T) {
+	for openBOSIcon, elionaIcon := range elionaIcons {
+		assert.Contains(t, appmodel.ElionaIcons, elionaIcon, "icon %s", openBOSIcon)
+		assert.Equal(t, normalizeEnumValue(openBOSIcon), openBOSIcon, "built-in icons must be normalized")
+	}
+}
+
+func TestConvertAssetTemplateSetsIcon(t *testing.T) {
+	assetType := convertAssetTemplateToAssetType(assetTemplate{ID: "ahu", Name: "AHU", Icon: "ahu"}, nil)
+	assert.Equal(t, "ventilation", assetType.GetIcon())
+
+	assetType = convertAssetTemplateToAssetType(assetTemplate{ID: "other", Name: "Other"}, nil)
+	assert.False(t, assetType.Icon.IsSet(), "templates without icon should get the default icon")
+}
+
+func TestUnknownIconsAreReportedOnce(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		_, ok := elionaIcon("Hovercraft", nil)
+		assert.False(t, ok)
+	}
+	assert.True(t, slices.Contains(UnknownValues(), UnknownValue{Enum: "icon", Value: "Hovercraft", Count: 1}))
+}
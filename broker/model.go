@@ -0,0 +1,199 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+import (
+	appmodel "open-bos/app/model"
+	"open-bos/complexdata"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+// ontologyModel holds the templates of one ontology together with the data
+// types and units they are built from, as seen by one configuration. It is
+// built for each fetched ontology and only read afterwards, so it can be used
+// concurrently and nothing is shared between configurations.
+type ontologyModel struct {
+	templates []assetTemplate
+	dataTypes map[string][]dataTypeUncomplexified // By data type ID, complex data types are unwrapped into their fields.
+	units     map[string]string                   // Unit symbol by unit ID.
+
+	// [datapoint-attribution]
+	datapointTemplates map[string]datapointTemplatePreprocessedInfo // By datapoint or property template ID.
+	templateTags       map[string][]string                          // By template ID.
+}
+
+// [datapoint-attribution]
+type datapointTemplatePreprocessedInfo struct {
+	name       string // datapoint name always
+	subtype    string
+	attributes []attributeTemplateInfo
+	tags       []string
+}
+type attributeTemplateInfo struct {
+	name       string // datatype (name or id) . uncomplexified path
+	unitID     string
+	unitSymbol string
+	format     Format
+}
+
+// newOntologyModel builds the model of an ontology whose datapoints are
+// already attributed, see attributeDatapoints. Datapoint units follow the unit
+// preferences and conversions of the configuration.
+func newOntologyModel(ontology ontologyDTO, orphanDatapoints []ontologyDatapointDTO, config appmodel.Configuration) *ontologyModel {
+	model := &ontologyModel{
+		dataTypes:          ontology.dataTypeMap(),
+		units:              ontology.unitMap(),
+		datapointTemplates: make(map[string]datapointTemplatePreprocessedInfo),
+		templateTags:       make(map[string][]string),
+	}
+	model.templates = model.assetTemplates(ontology, orphanDatapoints, config.UnitPreferences, config.UnitConversions)
+
+	for _, template := range model.templates {
+		model.templateTags[template.ID] = template.Tags
+		for _, dp := range template.Datapoints {
+			var attributes []attributeTemplateInfo
+			for _, attrib := range dp.Attributes {
+				attributes = append(attributes, attributeTemplateInfo{
+					name:       attrib.Name,
+					unitID:     attrib.UnitID,
+					unitSymbol: attrib.UnitSymbol,
+					format:     attrib.Format,
+				})
+			}
+			model.datapointTemplates[dp.ID] = datapointTemplatePreprocessedInfo{
+				name:       dp.Name,
+				subtype:    string(determineSubtype(ParseDirection(dp.Direction))),
+				attributes: attributes,
+				tags:       dp.Tags,
+			}
+		}
+		for _, prop := range template.Properties {
+			var attributes []attributeTemplateInfo
+			for _, attrib := range prop.Attributes {
+				attributes = append(attributes, attributeTemplateInfo{
+					name:   attrib.Name,
+					format: attrib.Format,
+				})
+			}
+			model.datapointTemplates[prop.ID] = datapointTemplatePreprocessedInfo{
+				name:       prop.Name,
+				subtype:    string(api.SUBTYPE_STATUS),
+				attributes: attributes,
+			}
+		}
+	}
+	return model
+}
+
+// dataTypeMap organizes datatypes in a map for simple lookup. Inside is a
+// slice to support complex data types.
+func (ontology ontologyDTO) dataTypeMap() map[string][]dataTypeUncomplexified {
+	// dataTypeComplexMap is an intermediate step to map dataTypes for lookup,
+	// yet without unwrapping complex types.
+	dataTypeComplexMap := make(map[string]ontologyDataTypeDTO)
+	for _, dt := range ontology.DataTypes {
+		dataTypeComplexMap[dt.ID] = dt
+	}
+	dataTypeMap := make(map[string][]dataTypeUncomplexified)
+	for _, dt := range ontology.DataTypes {
+		name := dt.Name
+		if name == "" {
+			// Name might be null, in that case let's use ID as a fallback.
+			name = dt.ID
+		}
+		dataTypeMap[dt.ID] = dt.unwrapComplexType(dataTypeComplexMap, name)
+	}
+	return dataTypeMap
+}
+
+func (ontology ontologyDTO) unitMap() map[string]string {
+	unitMap := make(map[string]string)
+	for _, unit := range ontology.Units {
+		unitMap[unit.ID] = unit.Symbol
+	}
+	return unitMap
+}
+
+// [datapoint-attribution] datapoints merges the datapoint template information
+// (name, subtype) with the datapoint and property instances (instance ID, data)
+// of an asset or space.
+func (m *ontologyModel) datapoints(datapoints []ontologyDatapointDTO, properties []ontologyPropertyDTO) []appmodel.Datapoint {
+	var dps []appmodel.Datapoint
+	for _, dp := range datapoints {
+		datapoint, ok := m.datapointTemplates[dp.TemplateID]
+		if !ok {
+			log.Warn("broker", "datapoint template not found for datapoint template %s", dp.TemplateID)
+			continue
+		}
+		dps = append(dps, appmodel.Datapoint{
+			Subtype:             datapoint.subtype,
+			ProviderID:          dp.ID,
+			AttributeNamePrefix: datapoint.name,
+			Attributes:          datapoint.appAttributes(),
+			Tags:                datapoint.tags,
+		})
+	}
+	for _, prop := range properties {
+		datapoint, ok := m.datapointTemplates[prop.TemplateID]
+		if !ok {
+			log.Warn("broker", "datapoint template not found for property template %s", prop.TemplateID)
+			continue
+		}
+		dp := appmodel.Datapoint{
+			Subtype:             datapoint.subtype,
+			ProviderID:          prop.ID,
+			AttributeNamePrefix: datapoint.name,
+			Attributes:          datapoint.appAttributes(),
+		}
+
+		if prop.Value != nil {
+			assetData := make(map[string]any)
+			// Complex decode support
+			if complexData, ok := prop.Value.(map[string]any); ok {
+				decodedData := complexdata.DecodeComplexData(complexData, dp.AttributeNamePrefix)
+				for k, v := range decodedData {
+					assetData[k] = v
+				}
+			} else {
+				// If not complex, find the attribute name and map directly
+				if len(dp.Attributes) != 1 {
+					log.Error("inconsistency", "received non-complex data %+v for property %v of datapoint %v, but found datapoint providerID %v with %v != 1 attributes", prop.Value, prop.ID, datapoint.name, dp.ProviderID, len(dp.Attributes))
+					continue
+				}
+				assetData[dp.Attributes[0].Name] = prop.Value
+			}
+			CoerceToEliona(dp.Attributes, assetData)
+			dp.Data = assetData
+		}
+		dps = append(dps, dp)
+	}
+	return dps
+}
+
+func (d datapointTemplatePreprocessedInfo) appAttributes() []appmodel.Attribute {
+	var attributes []appmodel.Attribute
+	for _, attributeInfo := range d.attributes {
+		attributes = append(attributes, appmodel.Attribute{
+			Name:       attributeInfo.name,
+			UnitID:     attributeInfo.unitID,
+			UnitSymbol: attributeInfo.unitSymbol,
+			Format:     string(attributeInfo.format),
+		})
+	}
+	return attributes
+}
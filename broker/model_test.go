@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	appmodel "open-bos/app/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOntologyModelsAreIsolated(t *testing.T) {
+	// Gateways of different configurations may use the same template IDs.
+	ontology := func(datapointName string) ontologyDTO {
+		return ontologyDTO{
+			AssetTemplates:     []ontologyAssetOrSpaceTemplateDTO{{ID: "sensor", Name: "Sensor"}},
+			DataTypes:          []ontologyDataTypeDTO{{ID: "value", Format: "float"}},
+			DatapointTemplates: []ontologyDatapointTemplateDTO{{ID: "dp-template", Name: datapointName, AssetTemplateID: "sensor", TypeID: "value", Direction: "Feedback"}},
+		}
+	}
+
+	models := make([]*ontologyModel, 10)
+	var wg sync.WaitGroup
+	for i := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models[i] = newOntologyModel(ontology(fmt.Sprintf("Value %d", i)), nil, appmodel.Configuration{Id: int64(i)})
+		}()
+	}
+	wg.Wait()
+
+	for i, model := range models {
+		datapoints := model.datapoints([]ontologyDatapointDTO{{ID: "dp", TemplateID: "dp-template"}}, nil)
+		if assert.Len(t, datapoints, 1) {
+			assert.Equal(t, fmt.Sprintf("Value %d", i), datapoints[0].AttributeNamePrefix)
+			assert.Equal(t, "input", datapoints[0].Subtype)
+		}
+	}
+}
+
+func TestOntologyModelDatapointsWithUnknownTemplate(t *testing.T) {
+	model := newOntologyModel(ontologyDTO{}, nil, appmodel.Configuration{})
+	datapoints := model.datapoints(
+		[]ontologyDatapointDTO{{ID: "dp", TemplateID: "unknown"}},
+		[]ontologyPropertyDTO{{ID: "prop", TemplateID: "unknown", Value: 1.0}},
+	)
+	assert.Empty(t, datapoints)
+}
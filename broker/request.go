@@ -17,63 +17,219 @@ package broker
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
+	appmodel "open-bos/app/model"
+
 	"github.com/eliona-smart-building-assistant/go-utils/log"
 )
 
-// const baseURL = "https://api.buildings.ability.abb/buildings/openbos/apiproxy/v1"
-// todo: change *scope* to prod as well
-// const baseURL = "http://localhost:5000"
-const baseURL = "https://dev.api.buildings.ability.abb/buildings/openbos/apiproxy/v1"
+// Production endpoints, used if a configuration does not specify its own. The
+// same defaults are set for the configuration table in init.sql.
+const (
+	DefaultBaseURL  = "https://api.buildings.ability.abb/buildings/openbos/apiproxy/v1"
+	DefaultTokenURL = "https://login.microsoftonline.com/372ee9e0-9ce0-4033-a64a-c07073a91ecd/oauth2/v2.0/token"
+	DefaultScope    = "api://openbos/.default"
+)
+
+// defaultRequestTimeout is used if the configuration does not specify a valid
+// timeout. It matches the default of the configuration table.
+const defaultRequestTimeout = 120 * time.Second
 
-const tokenURL = "https://login.microsoftonline.com/372ee9e0-9ce0-4033-a64a-c07073a91ecd/oauth2/v2.0/token"
+// Retry defaults, matching the defaults of the configuration table. Delays are
+// in milliseconds.
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = 500
+	DefaultRetryMaxDelay  = 30000
+)
+
+// tokenRefreshMargin defines how long before its expiry an access token gets
+// renewed, so that requests in flight never carry an expired token.
+const tokenRefreshMargin = time.Minute
 
 type openBOSClient struct {
 	gatewayID    string
 	httpClient   *http.Client
-	accessToken  string
 	clientID     string
 	clientSecret string
 	webhookURL   string
 	baseURL      string
 	tokenURL     string
+	scope        string
+	retry        retryPolicy
+
+	tokenMu     sync.Mutex
+	accessToken string
+	tokenExpiry time.Time // Zero if the token endpoint did not report the expiry.
 }
 
 // Defined as variable function to allow overriding in tests
-var newOpenBOSClient = func(gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL string) (*openBOSClient, error) {
+var newOpenBOSClient = func(ctx context.Context, gatewayID, clientID, clientSecret, webhookURL, baseURL, tokenURL, scope string, timeout time.Duration, retry retryPolicy) (*openBOSClient, error) {
 	client := &openBOSClient{
 		gatewayID:    gatewayID,
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   &http.Client{Timeout: timeout},
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		webhookURL:   webhookURL,
 		baseURL:      baseURL,
 		tokenURL:     tokenURL,
+		scope:        scope,
+		retry:        retry,
 	}
 
-	if err := client.authenticateWithClientCredentials(); err != nil {
+	if err := client.authenticateWithClientCredentials(ctx); err != nil {
 		return nil, err
 	}
 
 	return client, nil
 }
 
-func (c *openBOSClient) authenticateWithClientCredentials() error {
+// clientKey holds everything a client was constructed from. If any of it
+// changes, the cached client must not be used anymore.
+type clientKey struct {
+	gatewayID    string
+	clientID     string
+	clientSecret string
+	webhookURL   string
+	baseURL      string
+	tokenURL     string
+	scope        string
+	timeout      time.Duration
+	retry        retryPolicy
+}
+
+type cachedClient struct {
+	key    clientKey
+	client *openBOSClient
+}
+
+// clientRegistry keeps one client per configuration, so that the access token
+// is reused across calls instead of being requested for each of them.
+type clientRegistry struct {
+	mu      sync.Mutex
+	clients map[int64]cachedClient
+}
+
+var clients = clientRegistry{clients: make(map[int64]cachedClient)}
+
+func (r *clientRegistry) get(ctx context.Context, configID int64, key clientKey) (*openBOSClient, error) {
+	r.mu.Lock()
+	cached, ok := r.clients[configID]
+	r.mu.Unlock()
+	if ok && cached.key == key {
+		return cached.client, nil
+	}
+
+	// Authentication happens outside the lock to not block other configurations.
+	client, err := newOpenBOSClient(ctx, key.gatewayID, key.clientID, key.clientSecret, key.webhookURL, key.baseURL, key.tokenURL, key.scope, key.timeout, key.retry)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.clients[configID]; ok && cached.key == key {
+		// Someone else was faster, keep the client that is already in use.
+		return cached.client, nil
+	}
+	r.clients[configID] = cachedClient{key: key, client: client}
+	return client, nil
+}
+
+func (r *clientRegistry) remove(configID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, configID)
+}
+
+// getClient returns the cached client for the configuration, creating it if
+// there is none yet or if the configuration changed since.
+func getClient(ctx context.Context, config appmodel.Configuration) (*openBOSClient, error) {
+	return clients.get(ctx, config.Id, clientKey{
+		gatewayID:    config.Gwid,
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		webhookURL:   webhookBaseURL(config),
+		baseURL:      config.BaseURL,
+		tokenURL:     config.TokenURL,
+		scope:        config.Scope,
+		timeout:      requestTimeout(config),
+		retry:        newRetryPolicy(config),
+	})
+}
+
+// webhookBaseURL returns the URL below which the edge calls the webhooks of
+// the configuration. It contains the webhook secret, which is how the app
+// tells genuine calls from forged ones.
+func webhookBaseURL(config appmodel.Configuration) string {
+	webhookURL, err := url.JoinPath(config.AppPublicAPIURL, fmt.Sprint(config.Id), config.WebhookSecret)
+	if err != nil {
+		// Subscribing reports the malformed URL to the user.
+		return config.AppPublicAPIURL
+	}
+	return webhookURL
+}
+
+func requestTimeout(config appmodel.Configuration) time.Duration {
+	if config.RequestTimeout <= 0 {
+		return defaultRequestTimeout
+	}
+	return time.Duration(config.RequestTimeout) * time.Second
+}
+
+// ForgetClient drops the cached client of a configuration, e.g. after the
+// configuration was deleted.
+func ForgetClient(configID int64) {
+	clients.remove(configID)
+}
+
+// token returns a valid access token, requesting a new one if the current one
+// is missing or about to expire.
+func (c *openBOSClient) token(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.accessToken != "" && (c.tokenExpiry.IsZero() || time.Now().Before(c.tokenExpiry.Add(-tokenRefreshMargin))) {
+		return c.accessToken, nil
+	}
+	if err := c.authenticateWithClientCredentials(ctx); err != nil {
+		return "", fmt.Errorf("authenticating: %v", err)
+	}
+	return c.accessToken, nil
+}
+
+// invalidateToken discards the token if it is still the one that got rejected.
+// Another request might have replaced it in the meantime.
+func (c *openBOSClient) invalidateToken(rejected string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.accessToken == rejected {
+		c.accessToken = ""
+		c.tokenExpiry = time.Time{}
+	}
+}
+
+func (c *openBOSClient) authenticateWithClientCredentials(ctx context.Context) error {
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", c.clientID)
 	data.Set("client_secret", c.clientSecret)
-	data.Set("scope", "api://dev.openbos/.default")
+	data.Set("scope", c.scope)
 
-	req, err := http.NewRequest("POST", c.tokenURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("creating request: %v", err)
 	}
@@ -106,38 +262,219 @@ func (c *openBOSClient) authenticateWithClientCredentials() error {
 	}
 
 	c.accessToken = accessToken
+	c.tokenExpiry = time.Time{}
+	if expiresIn, ok := parseExpiresIn(tokenResponse["expires_in"]); ok {
+		c.tokenExpiry = time.Now().Add(expiresIn)
+	}
 	return nil
 }
 
-func (c *openBOSClient) doRequest(method, endpoint string, queryParams url.Values, body interface{}, result interface{}) error {
+// parseExpiresIn reads the token lifetime in seconds. Depending on the issuer it
+// is sent either as a JSON number or as a string.
+func parseExpiresIn(value any) (time.Duration, bool) {
+	var seconds float64
+	switch v := value.(type) {
+	case float64:
+		seconds = v
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		seconds = parsed
+	default:
+		return 0, false
+	}
+	if seconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// retryPolicy defines how often and after which delay failed requests are
+// repeated.
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func newRetryPolicy(config appmodel.Configuration) retryPolicy {
+	policy := retryPolicy{
+		maxRetries: int(config.MaxRetries),
+		baseDelay:  time.Duration(config.RetryBaseDelay) * time.Millisecond,
+		maxDelay:   time.Duration(config.RetryMaxDelay) * time.Millisecond,
+	}
+	if policy.maxRetries < 0 {
+		policy.maxRetries = 0
+	}
+	if policy.baseDelay <= 0 {
+		policy.baseDelay = DefaultRetryBaseDelay * time.Millisecond
+	}
+	if policy.maxDelay < policy.baseDelay {
+		policy.maxDelay = policy.baseDelay
+	}
+	return policy
+}
+
+// backoff returns the delay before the given retry (starting at 0). The delay
+// doubles with each retry up to maxDelay, and half of it is randomized so that
+// clients failing at the same time do not retry at the same time as well.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.baseDelay
+	for i := 0; i < retry && delay < p.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryableStatus tells whether a request that received the status code is
+// worth repeating. Non-idempotent requests are repeated only if the status
+// guarantees that the server did not process them.
+func isRetryableStatus(statusCode int, idempotent bool) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// parseRetryAfter reads the Retry-After header, which holds either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if date.Before(now) {
+			return 0, true
+		}
+		return date.Sub(now), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *openBOSClient) doRequest(ctx context.Context, method, endpoint string, queryParams url.Values, body interface{}, result interface{}) error {
 	url := fmt.Sprintf("%s/gateway/%s/api/v1/%s", c.baseURL, c.gatewayID, endpoint)
 	if queryParams != nil && len(queryParams) > 0 {
 		url += "?" + queryParams.Encode()
 	}
 
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		var err error
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("encoding request body: %v", err)
 		}
+	}
+
+	idempotent := method != http.MethodPost
+	tokenRenewed := false
+	for retry := 0; ; {
+		resp, accessToken, err := c.send(ctx, method, url, bodyBytes, body != nil)
+		if err != nil {
+			// A request that failed on the way might have reached the server, so
+			// only idempotent ones can be safely repeated.
+			if !idempotent || ctx.Err() != nil || retry >= c.retry.maxRetries {
+				return err
+			}
+			delay := c.retry.backoff(retry)
+			log.Debug("client", "%s %s failed, retrying in %v: %v", method, endpoint, delay, err)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("waiting for retry: %v", err)
+			}
+			retry++
+			continue
+		}
+
+		// A rejected token is renewed and the request repeated once. The token
+		// might have been revoked before its expiry.
+		if resp.StatusCode == http.StatusUnauthorized && !tokenRenewed {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			log.Debug("client", "access token rejected for %s %s, renewing it", method, endpoint)
+			c.invalidateToken(accessToken)
+			tokenRenewed = true
+			continue
+		}
+
+		if isRetryableStatus(resp.StatusCode, idempotent) && retry < c.retry.maxRetries {
+			delay := c.retry.backoff(retry)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > c.retry.maxDelay {
+					// Waiting that long would block the caller for too long.
+					return decodeResponse(resp, result)
+				}
+				delay = retryAfter
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			log.Debug("client", "%s %s returned status %d, retrying in %v", method, endpoint, resp.StatusCode, delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("waiting for retry: %v", err)
+			}
+			retry++
+			continue
+		}
+
+		return decodeResponse(resp, result)
+	}
+}
+
+// send makes a single attempt of the request. It returns the access token used,
+// so that it can be invalidated if rejected.
+func (c *openBOSClient) send(ctx context.Context, method, url string, bodyBytes []byte, hasBody bool) (*http.Response, string, error) {
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var bodyReader io.Reader
+	if hasBody {
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return fmt.Errorf("creating request: %v", err)
+		return nil, "", fmt.Errorf("creating request: %v", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
-	if body != nil {
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("requesting: %v", err)
+		return nil, "", fmt.Errorf("requesting: %v", err)
 	}
+	return resp, accessToken, nil
+}
+
+func decodeResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
@@ -146,7 +483,8 @@ func (c *openBOSClient) doRequest(method, endpoint string, queryParams url.Value
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		// Some endpoints answer with an empty body, the result stays zeroed then.
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil && !errors.Is(err, io.EOF) {
 			return fmt.Errorf("decoding response: %v", err)
 		}
 	}
@@ -154,7 +492,7 @@ func (c *openBOSClient) doRequest(method, endpoint string, queryParams url.Value
 	return nil
 }
 
-func (c *openBOSClient) doMockRequest(method, endpoint string, queryParams url.Values, body interface{}, result interface{}) error {
+func (c *openBOSClient) doMockRequest(ctx context.Context, method, endpoint string, queryParams url.Values, body interface{}, result interface{}) error {
 	url := fmt.Sprintf("%s/api/v1/%s", c.baseURL, endpoint)
 	if queryParams != nil && len(queryParams) > 0 {
 		url += "?" + queryParams.Encode()
@@ -169,7 +507,7 @@ func (c *openBOSClient) doMockRequest(method, endpoint string, queryParams url.V
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return fmt.Errorf("creating request: %v", err)
 	}
@@ -206,7 +544,8 @@ func (c *openBOSClient) doMockRequest(method, endpoint string, queryParams url.V
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		// Some endpoints answer with an empty body, the result stays zeroed then.
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil && !errors.Is(err, io.EOF) {
 			return fmt.Errorf("decoding response: %v", err)
 		}
 	}
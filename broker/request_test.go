@@ -0,0 +1,212 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	appmodel "open-bos/app/model"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenTestServer simulates the token endpoint and an API endpoint that
+// accepts only the most recently issued token.
+func newTokenTestServer(expiresIn int, tokenRequests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/oauth2/v2.0/token"):
+			n := tokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": %d}`, n, expiresIn)
+		case strings.Contains(r.URL.Path, "/api/v1/core/application/data/version"):
+			if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", tokenRequests.Load()) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintln(w, `2`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClientReusesToken(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenTestServer(3600, &tokenRequests)
+	defer ts.Close()
+
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retryPolicy{})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.getOntologyVersion(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), tokenRequests.Load(), "token should be requested only once")
+}
+
+func TestClientRefreshesTokenBeforeExpiry(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenTestServer(3600, &tokenRequests)
+	defer ts.Close()
+
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retryPolicy{})
+	assert.NoError(t, err)
+
+	// Pretend the token is about to expire.
+	client.tokenExpiry = time.Now().Add(tokenRefreshMargin / 2)
+
+	_, err = client.getOntologyVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), tokenRequests.Load(), "token should be refreshed proactively")
+}
+
+func TestClientRetriesOnceOnUnauthorized(t *testing.T) {
+	var tokenRequests atomic.Int32
+	ts := newTokenTestServer(3600, &tokenRequests)
+	defer ts.Close()
+
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retryPolicy{})
+	assert.NoError(t, err)
+
+	// Pretend the token got revoked on the server side.
+	client.accessToken = "revoked"
+
+	version, err := client.getOntologyVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), version)
+	assert.Equal(t, int32(2), tokenRequests.Load(), "token should be renewed after 401")
+}
+
+func TestParseExpiresIn(t *testing.T) {
+	d, ok := parseExpiresIn(3599.0)
+	assert.True(t, ok)
+	assert.Equal(t, 3599*time.Second, d)
+
+	d, ok = parseExpiresIn("3599")
+	assert.True(t, ok)
+	assert.Equal(t, 3599*time.Second, d)
+
+	_, ok = parseExpiresIn(nil)
+	assert.False(t, ok)
+}
+
+func TestClientHonoursRequestTimeout(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := newTokenTestServer(3600, &tokenRequests)
+	defer tokenServer.Close()
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprintln(w, `2`)
+	}))
+	defer slowServer.Close()
+
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", slowServer.URL, tokenServer.URL+"/oauth2/v2.0/token", DefaultScope, 50*time.Millisecond, retryPolicy{})
+	assert.NoError(t, err)
+
+	_, err = client.getOntologyVersion(context.Background())
+	assert.Error(t, err, "request should time out")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.getOntologyVersion(ctx)
+	assert.ErrorContains(t, err, context.Canceled.Error())
+}
+
+// newFlakyTestServer serves tokens and fails the first requests to the API
+// with the given status.
+func newFlakyTestServer(failures int32, status int, apiRequests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/oauth2/v2.0/token") {
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		if apiRequests.Add(1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprintln(w, `2`)
+	}))
+}
+
+func TestClientRetriesTransientErrors(t *testing.T) {
+	var apiRequests atomic.Int32
+	ts := newFlakyTestServer(2, http.StatusServiceUnavailable, &apiRequests)
+	defer ts.Close()
+
+	retry := retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: 10 * time.Millisecond}
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retry)
+	assert.NoError(t, err)
+
+	version, err := client.getOntologyVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), version)
+	assert.Equal(t, int32(3), apiRequests.Load())
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	var apiRequests atomic.Int32
+	ts := newFlakyTestServer(10, http.StatusGatewayTimeout, &apiRequests)
+	defer ts.Close()
+
+	retry := retryPolicy{maxRetries: 2, baseDelay: time.Millisecond, maxDelay: 10 * time.Millisecond}
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retry)
+	assert.NoError(t, err)
+
+	_, err = client.getOntologyVersion(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), apiRequests.Load())
+}
+
+func TestClientDoesNotRetryNonIdempotentOnBadGateway(t *testing.T) {
+	var apiRequests atomic.Int32
+	ts := newFlakyTestServer(1, http.StatusBadGateway, &apiRequests)
+	defer ts.Close()
+
+	retry := retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: 10 * time.Millisecond}
+	client, err := newOpenBOSClient(context.Background(), "test-gwid", "id", "secret", "", ts.URL, ts.URL+"/oauth2/v2.0/token", DefaultScope, defaultRequestTimeout, retry)
+	assert.NoError(t, err)
+
+	err = client.ackAlarm(context.Background(), ontologyAlarmAckDTO{SessionID: "session"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), apiRequests.Load())
+}
+
+func TestRetryBackoff(t *testing.T) {
+	retry := retryPolicy{maxRetries: 10, baseDelay: 100 * time.Millisecond, maxDelay: time.Second}
+	for i, expected := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		expected *= time.Millisecond
+		delay := retry.backoff(i)
+		assert.GreaterOrEqual(t, delay, expected/2, "retry %d", i)
+		assert.LessOrEqual(t, delay, expected, "retry %d", i)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("5", now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+func TestWebhookBaseURL(t *testing.T) {
+	config := appmodel.Configuration{Id: 3, AppPublicAPIURL: "https://eliona.example/apps-public/open-bos", WebhookSecret: "s3cret"}
+	assert.Equal(t, "https://eliona.example/apps-public/open-bos/3/s3cret", webhookBaseURL(config))
+}
@@ -0,0 +1,109 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package broker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+// Timestamps in the future by more than this are attributed to a clock skew
+// of the edge.
+const futureTimestampTolerance = 5 * time.Second
+
+// timestampLayouts are the formats OpenBOS uses for timestamps. Fractional
+// seconds are accepted after the seconds in all of them. Timestamps without an
+// offset are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"02/01/2006 15:04:05", // Live alarms
+}
+
+// ParseTimestamp parses a timestamp received from OpenBOS.
+func ParseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown timestamp format %q", value)
+}
+
+// TimestampStatistics describe how the timestamps received for a configuration
+// relate to the local clock.
+type TimestampStatistics struct {
+	Samples        int64
+	FutureSamples  int64         // Timestamps too far in the future.
+	ClampedSamples int64         // Future timestamps replaced by the local time.
+	MaxFutureSkew  time.Duration // How far the edge clock was ahead at most.
+	LastFutureAt   time.Time     // When the last timestamp in the future was received.
+}
+
+var timestampStatistics = struct {
+	sync.Mutex
+	byConfig map[int64]TimestampStatistics
+}{byConfig: make(map[int64]TimestampStatistics)}
+
+// CheckTimestamp records whether a timestamp received for the configuration is
+// ahead of the local clock when it was received. Timestamps in the past are not
+// counted, as values that are refreshed or delivered late carry old ones.
+// Timestamps too far in the future are replaced by the time they were received
+// if clamp is set. The time of receipt is taken by the caller, as values may
+// wait in a queue before they are checked.
+func CheckTimestamp(configID int64, timestamp time.Time, receivedAt time.Time, clamp bool) time.Time {
+	skew := timestamp.Sub(receivedAt)
+
+	timestampStatistics.Lock()
+	stats := timestampStatistics.byConfig[configID]
+	stats.Samples++
+	future := skew > futureTimestampTolerance
+	firstFuture := future && stats.FutureSamples == 0
+	if future {
+		stats.FutureSamples++
+		stats.MaxFutureSkew = max(stats.MaxFutureSkew, skew)
+		stats.LastFutureAt = receivedAt
+		if clamp {
+			stats.ClampedSamples++
+		}
+	}
+	timestampStatistics.byConfig[configID] = stats
+	timestampStatistics.Unlock()
+
+	if firstFuture {
+		log.Warn("broker", "Config %d received timestamp %v, which is %v in the future. The clock of the edge seems to be ahead.", configID, timestamp, skew.Round(time.Second))
+	}
+	if future && clamp {
+		return receivedAt
+	}
+	return timestamp
+}
+
+// GetTimestampStatistics returns the statistics of the timestamps received for
+// the configuration since the app started.
+func GetTimestampStatistics(configID int64) TimestampStatistics {
+	timestampStatistics.Lock()
+	defer timestampStatistics.Unlock()
+	return timestampStatistics.byConfig[configID]
+}
@@ -0,0 +1,63 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2024, 3, 5, 14, 30, 15, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2024-03-05T14:30:15Z":           expected,
+		"2024-03-05T14:30:15.250Z":       expected.Add(250 * time.Millisecond),
+		"2024-03-05T15:30:15+01:00":      expected,
+		"2024-03-05T15:30:15.5+0100":     expected.Add(500 * time.Millisecond),
+		"2024-03-05T14:30:15":            expected,
+		"2024-03-05T14:30:15.1234567":    expected.Add(123456700 * time.Nanosecond),
+		"2024-03-05 14:30:15":            expected,
+		"2024-03-05 16:30:15+02:00":      expected,
+		"05/03/2024 14:30:15":            expected,
+		"05/03/2024 14:30:15.750":        expected.Add(750 * time.Millisecond),
+		" 2024-03-05T14:30:15.000000Z  ": expected,
+	}
+	for input, want := range tests {
+		got, err := ParseTimestamp(input)
+		require.NoError(t, err, "input %q", input)
+		assert.True(t, want.Equal(got), "input %q: expected %v, got %v", input, want, got)
+	}
+
+	for _, input := range []string{"", "yesterday", "2024-13-05T14:30:15Z", "1709649015"} {
+		_, err := ParseTimestamp(input)
+		assert.Error(t, err, "input %q", input)
+	}
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	const configID = -17
+	now := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+
+	past := now.Add(-time.Hour)
+	assert.Equal(t, past, CheckTimestamp(configID, past, now, true), "timestamps in the past are kept")
+
+	slightlyAhead := now.Add(2 * time.Second)
+	assert.Equal(t, slightlyAhead, CheckTimestamp(configID, slightlyAhead, now, true), "small skews are tolerated")
+
+	ahead := now.Add(time.Minute)
+	assert.Equal(t, ahead, CheckTimestamp(configID, ahead, now, false), "timestamps are only clamped if requested")
+	assert.Equal(t, now, CheckTimestamp(configID, now.Add(30*time.Second), now, true))
+
+	slightlyBehind := now.Add(-30 * time.Second)
+	assert.Equal(t, slightlyBehind, CheckTimestamp(configID, slightlyBehind, now, true), "delivery delays are tolerated")
+
+	assert.Equal(t, TimestampStatistics{
+		Samples:        5,
+		FutureSamples:  2,
+		ClampedSamples: 1,
+		MaxFutureSkew:  time.Minute,
+		LastFutureAt:   now,
+	}, GetTimestampStatistics(configID))
+	assert.Equal(t, TimestampStatistics{}, GetTimestampStatistics(configID-1))
+}
@@ -30,6 +30,7 @@ type Asset struct {
 	GlobalAssetID   string     `boil:"global_asset_id" json:"global_asset_id" toml:"global_asset_id" yaml:"global_asset_id"`
 	ProviderID      string     `boil:"provider_id" json:"provider_id" toml:"provider_id" yaml:"provider_id"`
 	AssetID         null.Int32 `boil:"asset_id" json:"asset_id,omitempty" toml:"asset_id" yaml:"asset_id,omitempty"`
+	Stale           bool       `boil:"stale" json:"stale" toml:"stale" yaml:"stale"`
 
 	R *assetR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L assetL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -42,6 +43,7 @@ var AssetColumns = struct {
 	GlobalAssetID   string
 	ProviderID      string
 	AssetID         string
+	Stale           string
 }{
 	ID:              "id",
 	ConfigurationID: "configuration_id",
@@ -49,6 +51,7 @@ var AssetColumns = struct {
 	GlobalAssetID:   "global_asset_id",
 	ProviderID:      "provider_id",
 	AssetID:         "asset_id",
+	Stale:           "stale",
 }
 
 var AssetTableColumns = struct {
@@ -58,6 +61,7 @@ var AssetTableColumns = struct {
 	GlobalAssetID   string
 	ProviderID      string
 	AssetID         string
+	Stale           string
 }{
 	ID:              "asset.id",
 	ConfigurationID: "asset.configuration_id",
@@ -65,6 +69,7 @@ var AssetTableColumns = struct {
 	GlobalAssetID:   "asset.global_asset_id",
 	ProviderID:      "asset.provider_id",
 	AssetID:         "asset.asset_id",
+	Stale:           "asset.stale",
 }
 
 // Generated where
@@ -107,6 +112,15 @@ func (w whereHelpernull_Int32) NIN(slice []int32) qm.QueryMod {
 func (w whereHelpernull_Int32) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
 func (w whereHelpernull_Int32) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
+type whereHelperbool struct{ field string }
+
+func (w whereHelperbool) EQ(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperbool) NEQ(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperbool) LT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperbool) LTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperbool) GT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperbool) GTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+
 var AssetWhere = struct {
 	ID              whereHelperint64
 	ConfigurationID whereHelperint64
@@ -114,6 +128,7 @@ var AssetWhere = struct {
 	GlobalAssetID   whereHelperstring
 	ProviderID      whereHelperstring
 	AssetID         whereHelpernull_Int32
+	Stale           whereHelperbool
 }{
 	ID:              whereHelperint64{field: "\"open_bos\".\"asset\".\"id\""},
 	ConfigurationID: whereHelperint64{field: "\"open_bos\".\"asset\".\"configuration_id\""},
@@ -121,6 +136,7 @@ var AssetWhere = struct {
 	GlobalAssetID:   whereHelperstring{field: "\"open_bos\".\"asset\".\"global_asset_id\""},
 	ProviderID:      whereHelperstring{field: "\"open_bos\".\"asset\".\"provider_id\""},
 	AssetID:         whereHelpernull_Int32{field: "\"open_bos\".\"asset\".\"asset_id\""},
+	Stale:           whereHelperbool{field: "\"open_bos\".\"asset\".\"stale\""},
 }
 
 // AssetRels is where relationship names are stored.
@@ -161,9 +177,9 @@ func (r *assetR) GetOpenbosDatapoints() OpenbosDatapointSlice {
 type assetL struct{}
 
 var (
-	assetAllColumns            = []string{"id", "configuration_id", "project_id", "global_asset_id", "provider_id", "asset_id"}
+	assetAllColumns            = []string{"id", "configuration_id", "project_id", "global_asset_id", "provider_id", "asset_id", "stale"}
 	assetColumnsWithoutDefault = []string{"project_id", "global_asset_id", "provider_id"}
-	assetColumnsWithDefault    = []string{"id", "configuration_id", "asset_id"}
+	assetColumnsWithDefault    = []string{"id", "configuration_id", "asset_id", "stale"}
 	assetPrimaryKeyColumns     = []string{"id"}
 	assetGeneratedColumns      = []string{}
 )
@@ -8,11 +8,15 @@ var TableNames = struct {
 	Asset            string
 	Configuration    string
 	ElionaAttribute  string
+	OntologySnapshot string
 	OpenbosDatapoint string
+	Subscription     string
 }{
 	Alarm:            "alarm",
 	Asset:            "asset",
 	Configuration:    "configuration",
 	ElionaAttribute:  "eliona_attribute",
+	OntologySnapshot: "ontology_snapshot",
 	OpenbosDatapoint: "openbos_datapoint",
+	Subscription:     "subscription",
 }
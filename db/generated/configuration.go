@@ -24,82 +24,157 @@ import (
 
 // Configuration is an object representing the database table.
 type Configuration struct {
-	ID              int64             `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Gwid            string            `boil:"gwid" json:"gwid" toml:"gwid" yaml:"gwid"`
-	ClientID        string            `boil:"client_id" json:"client_id" toml:"client_id" yaml:"client_id"`
-	ClientSecret    string            `boil:"client_secret" json:"client_secret" toml:"client_secret" yaml:"client_secret"`
-	OntologyVersion int32             `boil:"ontology_version" json:"ontology_version" toml:"ontology_version" yaml:"ontology_version"`
-	AppPublicAPIURL string            `boil:"app_public_api_url" json:"app_public_api_url" toml:"app_public_api_url" yaml:"app_public_api_url"`
-	RefreshInterval int32             `boil:"refresh_interval" json:"refresh_interval" toml:"refresh_interval" yaml:"refresh_interval"`
-	RequestTimeout  int32             `boil:"request_timeout" json:"request_timeout" toml:"request_timeout" yaml:"request_timeout"`
-	AssetFilter     types.JSON        `boil:"asset_filter" json:"asset_filter" toml:"asset_filter" yaml:"asset_filter"`
-	Active          bool              `boil:"active" json:"active" toml:"active" yaml:"active"`
-	Enable          bool              `boil:"enable" json:"enable" toml:"enable" yaml:"enable"`
-	ProjectIds      types.StringArray `boil:"project_ids" json:"project_ids" toml:"project_ids" yaml:"project_ids"`
-	UserID          string            `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	ID                    int64             `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Gwid                  string            `boil:"gwid" json:"gwid" toml:"gwid" yaml:"gwid"`
+	ClientID              string            `boil:"client_id" json:"client_id" toml:"client_id" yaml:"client_id"`
+	ClientSecret          string            `boil:"client_secret" json:"client_secret" toml:"client_secret" yaml:"client_secret"`
+	OntologyVersion       int32             `boil:"ontology_version" json:"ontology_version" toml:"ontology_version" yaml:"ontology_version"`
+	AppPublicAPIURL       string            `boil:"app_public_api_url" json:"app_public_api_url" toml:"app_public_api_url" yaml:"app_public_api_url"`
+	WebhookSecret         string            `boil:"webhook_secret" json:"webhook_secret" toml:"webhook_secret" yaml:"webhook_secret"`
+	BaseURL               string            `boil:"base_url" json:"base_url" toml:"base_url" yaml:"base_url"`
+	TokenURL              string            `boil:"token_url" json:"token_url" toml:"token_url" yaml:"token_url"`
+	Scope                 string            `boil:"scope" json:"scope" toml:"scope" yaml:"scope"`
+	RefreshInterval       int32             `boil:"refresh_interval" json:"refresh_interval" toml:"refresh_interval" yaml:"refresh_interval"`
+	RequestTimeout        int32             `boil:"request_timeout" json:"request_timeout" toml:"request_timeout" yaml:"request_timeout"`
+	MaxRetries            int32             `boil:"max_retries" json:"max_retries" toml:"max_retries" yaml:"max_retries"`
+	RetryBaseDelay        int32             `boil:"retry_base_delay" json:"retry_base_delay" toml:"retry_base_delay" yaml:"retry_base_delay"`
+	RetryMaxDelay         int32             `boil:"retry_max_delay" json:"retry_max_delay" toml:"retry_max_delay" yaml:"retry_max_delay"`
+	DeletionPolicy        string            `boil:"deletion_policy" json:"deletion_policy" toml:"deletion_policy" yaml:"deletion_policy"`
+	BadQualityPolicy      string            `boil:"bad_quality_policy" json:"bad_quality_policy" toml:"bad_quality_policy" yaml:"bad_quality_policy"`
+	ClampFutureTimestamps bool              `boil:"clamp_future_timestamps" json:"clamp_future_timestamps" toml:"clamp_future_timestamps" yaml:"clamp_future_timestamps"`
+	UnitPreferences       types.JSON        `boil:"unit_preferences" json:"unit_preferences" toml:"unit_preferences" yaml:"unit_preferences"`
+	UnitConversions       types.JSON        `boil:"unit_conversions" json:"unit_conversions" toml:"unit_conversions" yaml:"unit_conversions"`
+	TagPrefix             string            `boil:"tag_prefix" json:"tag_prefix" toml:"tag_prefix" yaml:"tag_prefix"`
+	TagAllowList          types.JSON        `boil:"tag_allow_list" json:"tag_allow_list" toml:"tag_allow_list" yaml:"tag_allow_list"`
+	IconMapping           types.JSON        `boil:"icon_mapping" json:"icon_mapping" toml:"icon_mapping" yaml:"icon_mapping"`
+	AssetFilter           types.JSON        `boil:"asset_filter" json:"asset_filter" toml:"asset_filter" yaml:"asset_filter"`
+	Active                bool              `boil:"active" json:"active" toml:"active" yaml:"active"`
+	Enable                bool              `boil:"enable" json:"enable" toml:"enable" yaml:"enable"`
+	ProjectIds            types.StringArray `boil:"project_ids" json:"project_ids" toml:"project_ids" yaml:"project_ids"`
+	UserID                string            `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
 
 	R *configurationR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L configurationL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var ConfigurationColumns = struct {
-	ID              string
-	Gwid            string
-	ClientID        string
-	ClientSecret    string
-	OntologyVersion string
-	AppPublicAPIURL string
-	RefreshInterval string
-	RequestTimeout  string
-	AssetFilter     string
-	Active          string
-	Enable          string
-	ProjectIds      string
-	UserID          string
+	ID                    string
+	Gwid                  string
+	ClientID              string
+	ClientSecret          string
+	OntologyVersion       string
+	AppPublicAPIURL       string
+	WebhookSecret         string
+	BaseURL               string
+	TokenURL              string
+	Scope                 string
+	RefreshInterval       string
+	RequestTimeout        string
+	MaxRetries            string
+	RetryBaseDelay        string
+	RetryMaxDelay         string
+	DeletionPolicy        string
+	BadQualityPolicy      string
+	ClampFutureTimestamps string
+	UnitPreferences       string
+	UnitConversions       string
+	TagPrefix             string
+	TagAllowList          string
+	IconMapping           string
+	AssetFilter           string
+	Active                string
+	Enable                string
+	ProjectIds            string
+	UserID                string
 }{
-	ID:              "id",
-	Gwid:            "gwid",
-	ClientID:        "client_id",
-	ClientSecret:    "client_secret",
-	OntologyVersion: "ontology_version",
-	AppPublicAPIURL: "app_public_api_url",
-	RefreshInterval: "refresh_interval",
-	RequestTimeout:  "request_timeout",
-	AssetFilter:     "asset_filter",
-	Active:          "active",
-	Enable:          "enable",
-	ProjectIds:      "project_ids",
-	UserID:          "user_id",
+	ID:                    "id",
+	Gwid:                  "gwid",
+	ClientID:              "client_id",
+	ClientSecret:          "client_secret",
+	OntologyVersion:       "ontology_version",
+	AppPublicAPIURL:       "app_public_api_url",
+	WebhookSecret:         "webhook_secret",
+	BaseURL:               "base_url",
+	TokenURL:              "token_url",
+	Scope:                 "scope",
+	RefreshInterval:       "refresh_interval",
+	RequestTimeout:        "request_timeout",
+	MaxRetries:            "max_retries",
+	RetryBaseDelay:        "retry_base_delay",
+	RetryMaxDelay:         "retry_max_delay",
+	DeletionPolicy:        "deletion_policy",
+	BadQualityPolicy:      "bad_quality_policy",
+	ClampFutureTimestamps: "clamp_future_timestamps",
+	UnitPreferences:       "unit_preferences",
+	UnitConversions:       "unit_conversions",
+	TagPrefix:             "tag_prefix",
+	TagAllowList:          "tag_allow_list",
+	IconMapping:           "icon_mapping",
+	AssetFilter:           "asset_filter",
+	Active:                "active",
+	Enable:                "enable",
+	ProjectIds:            "project_ids",
+	UserID:                "user_id",
 }
 
 var ConfigurationTableColumns = struct {
-	ID              string
-	Gwid            string
-	ClientID        string
-	ClientSecret    string
-	OntologyVersion string
-	AppPublicAPIURL string
-	RefreshInterval string
-	RequestTimeout  string
-	AssetFilter     string
-	Active          string
-	Enable          string
-	ProjectIds      string
-	UserID          string
+	ID                    string
+	Gwid                  string
+	ClientID              string
+	ClientSecret          string
+	OntologyVersion       string
+	AppPublicAPIURL       string
+	WebhookSecret         string
+	BaseURL               string
+	TokenURL              string
+	Scope                 string
+	RefreshInterval       string
+	RequestTimeout        string
+	MaxRetries            string
+	RetryBaseDelay        string
+	RetryMaxDelay         string
+	DeletionPolicy        string
+	BadQualityPolicy      string
+	ClampFutureTimestamps string
+	UnitPreferences       string
+	UnitConversions       string
+	TagPrefix             string
+	TagAllowList          string
+	IconMapping           string
+	AssetFilter           string
+	Active                string
+	Enable                string
+	ProjectIds            string
+	UserID                string
 }{
-	ID:              "configuration.id",
-	Gwid:            "configuration.gwid",
-	ClientID:        "configuration.client_id",
-	ClientSecret:    "configuration.client_secret",
-	OntologyVersion: "configuration.ontology_version",
-	AppPublicAPIURL: "configuration.app_public_api_url",
-	RefreshInterval: "configuration.refresh_interval",
-	RequestTimeout:  "configuration.request_timeout",
-	AssetFilter:     "configuration.asset_filter",
-	Active:          "configuration.active",
-	Enable:          "configuration.enable",
-	ProjectIds:      "configuration.project_ids",
-	UserID:          "configuration.user_id",
+	ID:                    "configuration.id",
+	Gwid:                  "configuration.gwid",
+	ClientID:              "configuration.client_id",
+	ClientSecret:          "configuration.client_secret",
+	OntologyVersion:       "configuration.ontology_version",
+	AppPublicAPIURL:       "configuration.app_public_api_url",
+	WebhookSecret:         "configuration.webhook_secret",
+	BaseURL:               "configuration.base_url",
+	TokenURL:              "configuration.token_url",
+	Scope:                 "configuration.scope",
+	RefreshInterval:       "configuration.refresh_interval",
+	RequestTimeout:        "configuration.request_timeout",
+	MaxRetries:            "configuration.max_retries",
+	RetryBaseDelay:        "configuration.retry_base_delay",
+	RetryMaxDelay:         "configuration.retry_max_delay",
+	DeletionPolicy:        "configuration.deletion_policy",
+	BadQualityPolicy:      "configuration.bad_quality_policy",
+	ClampFutureTimestamps: "configuration.clamp_future_timestamps",
+	UnitPreferences:       "configuration.unit_preferences",
+	UnitConversions:       "configuration.unit_conversions",
+	TagPrefix:             "configuration.tag_prefix",
+	TagAllowList:          "configuration.tag_allow_list",
+	IconMapping:           "configuration.icon_mapping",
+	AssetFilter:           "configuration.asset_filter",
+	Active:                "configuration.active",
+	Enable:                "configuration.enable",
+	ProjectIds:            "configuration.project_ids",
+	UserID:                "configuration.user_id",
 }
 
 // Generated where
@@ -125,15 +200,6 @@ func (w whereHelpertypes_JSON) GTE(x types.JSON) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GTE, x)
 }
 
-type whereHelperbool struct{ field string }
-
-func (w whereHelperbool) EQ(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperbool) NEQ(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperbool) LT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperbool) LTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperbool) GT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperbool) GTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-
 type whereHelpertypes_StringArray struct{ field string }
 
 func (w whereHelpertypes_StringArray) EQ(x types.StringArray) qm.QueryMod {
@@ -156,45 +222,81 @@ func (w whereHelpertypes_StringArray) GTE(x types.StringArray) qm.QueryMod {
 }
 
 var ConfigurationWhere = struct {
-	ID              whereHelperint64
-	Gwid            whereHelperstring
-	ClientID        whereHelperstring
-	ClientSecret    whereHelperstring
-	OntologyVersion whereHelperint32
-	AppPublicAPIURL whereHelperstring
-	RefreshInterval whereHelperint32
-	RequestTimeout  whereHelperint32
-	AssetFilter     whereHelpertypes_JSON
-	Active          whereHelperbool
-	Enable          whereHelperbool
-	ProjectIds      whereHelpertypes_StringArray
-	UserID          whereHelperstring
+	ID                    whereHelperint64
+	Gwid                  whereHelperstring
+	ClientID              whereHelperstring
+	ClientSecret          whereHelperstring
+	OntologyVersion       whereHelperint32
+	AppPublicAPIURL       whereHelperstring
+	WebhookSecret         whereHelperstring
+	BaseURL               whereHelperstring
+	TokenURL              whereHelperstring
+	Scope                 whereHelperstring
+	RefreshInterval       whereHelperint32
+	RequestTimeout        whereHelperint32
+	MaxRetries            whereHelperint32
+	RetryBaseDelay        whereHelperint32
+	RetryMaxDelay         whereHelperint32
+	DeletionPolicy        whereHelperstring
+	BadQualityPolicy      whereHelperstring
+	ClampFutureTimestamps whereHelperbool
+	UnitPreferences       whereHelpertypes_JSON
+	UnitConversions       whereHelpertypes_JSON
+	TagPrefix             whereHelperstring
+	TagAllowList          whereHelpertypes_JSON
+	IconMapping           whereHelpertypes_JSON
+	AssetFilter           whereHelpertypes_JSON
+	Active                whereHelperbool
+	Enable                whereHelperbool
+	ProjectIds            whereHelpertypes_StringArray
+	UserID                whereHelperstring
 }{
-	ID:              whereHelperint64{field: "\"open_bos\".\"configuration\".\"id\""},
-	Gwid:            whereHelperstring{field: "\"open_bos\".\"configuration\".\"gwid\""},
-	ClientID:        whereHelperstring{field: "\"open_bos\".\"configuration\".\"client_id\""},
-	ClientSecret:    whereHelperstring{field: "\"open_bos\".\"configuration\".\"client_secret\""},
-	OntologyVersion: whereHelperint32{field: "\"open_bos\".\"configuration\".\"ontology_version\""},
-	AppPublicAPIURL: whereHelperstring{field: "\"open_bos\".\"configuration\".\"app_public_api_url\""},
-	RefreshInterval: whereHelperint32{field: "\"open_bos\".\"configuration\".\"refresh_interval\""},
-	RequestTimeout:  whereHelperint32{field: "\"open_bos\".\"configuration\".\"request_timeout\""},
-	AssetFilter:     whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"asset_filter\""},
-	Active:          whereHelperbool{field: "\"open_bos\".\"configuration\".\"active\""},
-	Enable:          whereHelperbool{field: "\"open_bos\".\"configuration\".\"enable\""},
-	ProjectIds:      whereHelpertypes_StringArray{field: "\"open_bos\".\"configuration\".\"project_ids\""},
-	UserID:          whereHelperstring{field: "\"open_bos\".\"configuration\".\"user_id\""},
+	ID:                    whereHelperint64{field: "\"open_bos\".\"configuration\".\"id\""},
+	Gwid:                  whereHelperstring{field: "\"open_bos\".\"configuration\".\"gwid\""},
+	ClientID:              whereHelperstring{field: "\"open_bos\".\"configuration\".\"client_id\""},
+	ClientSecret:          whereHelperstring{field: "\"open_bos\".\"configuration\".\"client_secret\""},
+	OntologyVersion:       whereHelperint32{field: "\"open_bos\".\"configuration\".\"ontology_version\""},
+	AppPublicAPIURL:       whereHelperstring{field: "\"open_bos\".\"configuration\".\"app_public_api_url\""},
+	WebhookSecret:         whereHelperstring{field: "\"open_bos\".\"configuration\".\"webhook_secret\""},
+	BaseURL:               whereHelperstring{field: "\"open_bos\".\"configuration\".\"base_url\""},
+	TokenURL:              whereHelperstring{field: "\"open_bos\".\"configuration\".\"token_url\""},
+	Scope:                 whereHelperstring{field: "\"open_bos\".\"configuration\".\"scope\""},
+	RefreshInterval:       whereHelperint32{field: "\"open_bos\".\"configuration\".\"refresh_interval\""},
+	RequestTimeout:        whereHelperint32{field: "\"open_bos\".\"configuration\".\"request_timeout\""},
+	MaxRetries:            whereHelperint32{field: "\"open_bos\".\"configuration\".\"max_retries\""},
+	RetryBaseDelay:        whereHelperint32{field: "\"open_bos\".\"configuration\".\"retry_base_delay\""},
+	RetryMaxDelay:         whereHelperint32{field: "\"open_bos\".\"configuration\".\"retry_max_delay\""},
+	DeletionPolicy:        whereHelperstring{field: "\"open_bos\".\"configuration\".\"deletion_policy\""},
+	BadQualityPolicy:      whereHelperstring{field: "\"open_bos\".\"configuration\".\"bad_quality_policy\""},
+	ClampFutureTimestamps: whereHelperbool{field: "\"open_bos\".\"configuration\".\"clamp_future_timestamps\""},
+	UnitPreferences:       whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"unit_preferences\""},
+	UnitConversions:       whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"unit_conversions\""},
+	TagPrefix:             whereHelperstring{field: "\"open_bos\".\"configuration\".\"tag_prefix\""},
+	TagAllowList:          whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"tag_allow_list\""},
+	IconMapping:           whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"icon_mapping\""},
+	AssetFilter:           whereHelpertypes_JSON{field: "\"open_bos\".\"configuration\".\"asset_filter\""},
+	Active:                whereHelperbool{field: "\"open_bos\".\"configuration\".\"active\""},
+	Enable:                whereHelperbool{field: "\"open_bos\".\"configuration\".\"enable\""},
+	ProjectIds:            whereHelpertypes_StringArray{field: "\"open_bos\".\"configuration\".\"project_ids\""},
+	UserID:                whereHelperstring{field: "\"open_bos\".\"configuration\".\"user_id\""},
 }
 
 // ConfigurationRels is where relationship names are stored.
 var ConfigurationRels = struct {
-	Assets string
+	OntologySnapshot string
+	Assets           string
+	Subscriptions    string
 }{
-	Assets: "Assets",
+	OntologySnapshot: "OntologySnapshot",
+	Assets:           "Assets",
+	Subscriptions:    "Subscriptions",
 }
 
 // configurationR is where relationships are stored.
 type configurationR struct {
-	Assets AssetSlice `boil:"Assets" json:"Assets" toml:"Assets" yaml:"Assets"`
+	OntologySnapshot *OntologySnapshot `boil:"OntologySnapshot" json:"OntologySnapshot" toml:"OntologySnapshot" yaml:"OntologySnapshot"`
+	Assets           AssetSlice        `boil:"Assets" json:"Assets" toml:"Assets" yaml:"Assets"`
+	Subscriptions    SubscriptionSlice `boil:"Subscriptions" json:"Subscriptions" toml:"Subscriptions" yaml:"Subscriptions"`
 }
 
 // NewStruct creates a new relationship struct
@@ -202,6 +304,13 @@ func (*configurationR) NewStruct() *configurationR {
 	return &configurationR{}
 }
 
+func (r *configurationR) GetOntologySnapshot() *OntologySnapshot {
+	if r == nil {
+		return nil
+	}
+	return r.OntologySnapshot
+}
+
 func (r *configurationR) GetAssets() AssetSlice {
 	if r == nil {
 		return nil
@@ -209,13 +318,20 @@ func (r *configurationR) GetAssets() AssetSlice {
 	return r.Assets
 }
 
+func (r *configurationR) GetSubscriptions() SubscriptionSlice {
+	if r == nil {
+		return nil
+	}
+	return r.Subscriptions
+}
+
 // configurationL is where Load methods for each relationship are stored.
 type configurationL struct{}
 
 var (
-	configurationAllColumns            = []string{"id", "gwid", "client_id", "client_secret", "ontology_version", "app_public_api_url", "refresh_interval", "request_timeout", "asset_filter", "active", "enable", "project_ids", "user_id"}
+	configurationAllColumns            = []string{"id", "gwid", "client_id", "client_secret", "ontology_version", "app_public_api_url", "webhook_secret", "base_url", "token_url", "scope", "refresh_interval", "request_timeout", "max_retries", "retry_base_delay", "retry_max_delay", "deletion_policy", "bad_quality_policy", "clamp_future_timestamps", "unit_preferences", "unit_conversions", "tag_prefix", "tag_allow_list", "icon_mapping", "asset_filter", "active", "enable", "project_ids", "user_id"}
 	configurationColumnsWithoutDefault = []string{"gwid", "client_id", "client_secret", "ontology_version", "app_public_api_url", "asset_filter", "project_ids", "user_id"}
-	configurationColumnsWithDefault    = []string{"id", "refresh_interval", "request_timeout", "active", "enable"}
+	configurationColumnsWithDefault    = []string{"id", "webhook_secret", "base_url", "token_url", "scope", "refresh_interval", "request_timeout", "max_retries", "retry_base_delay", "retry_max_delay", "deletion_policy", "bad_quality_policy", "clamp_future_timestamps", "unit_preferences", "unit_conversions", "tag_prefix", "tag_allow_list", "icon_mapping", "active", "enable"}
 	configurationPrimaryKeyColumns     = []string{"id"}
 	configurationGeneratedColumns      = []string{}
 )
@@ -545,6 +661,17 @@ func (q configurationQuery) Exists(ctx context.Context, exec boil.ContextExecuto
 	return count > 0, nil
 }
 
+// OntologySnapshot pointed to by the foreign key.
+func (o *Configuration) OntologySnapshot(mods ...qm.QueryMod) ontologySnapshotQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"configuration_id\" = ?", o.ID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return OntologySnapshots(queryMods...)
+}
+
 // Assets retrieves all the asset's Assets with an executor.
 func (o *Configuration) Assets(mods ...qm.QueryMod) assetQuery {
 	var queryMods []qm.QueryMod
@@ -559,6 +686,137 @@ func (o *Configuration) Assets(mods ...qm.QueryMod) assetQuery {
 	return Assets(queryMods...)
 }
 
+// Subscriptions retrieves all the subscription's Subscriptions with an executor.
+func (o *Configuration) Subscriptions(mods ...qm.QueryMod) subscriptionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"open_bos\".\"subscription\".\"configuration_id\"=?", o.ID),
+	)
+
+	return Subscriptions(queryMods...)
+}
+
+// LoadOntologySnapshot allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-1 relationship.
+func (configurationL) LoadOntologySnapshot(ctx context.Context, e boil.ContextExecutor, singular bool, maybeConfiguration interface{}, mods queries.Applicator) error {
+	var slice []*Configuration
+	var object *Configuration
+
+	if singular {
+		var ok bool
+		object, ok = maybeConfiguration.(*Configuration)
+		if !ok {
+			object = new(Configuration)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeConfiguration)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeConfiguration))
+			}
+		}
+	} else {
+		s, ok := maybeConfiguration.(*[]*Configuration)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeConfiguration)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeConfiguration))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &configurationR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &configurationR{}
+			}
+
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`open_bos.ontology_snapshot`),
+		qm.WhereIn(`open_bos.ontology_snapshot.configuration_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load OntologySnapshot")
+	}
+
+	var resultSlice []*OntologySnapshot
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice OntologySnapshot")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for ontology_snapshot")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for ontology_snapshot")
+	}
+
+	if len(ontologySnapshotAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.OntologySnapshot = foreign
+		if foreign.R == nil {
+			foreign.R = &ontologySnapshotR{}
+		}
+		foreign.R.Configuration = object
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.ID == foreign.ConfigurationID {
+				local.R.OntologySnapshot = foreign
+				if foreign.R == nil {
+					foreign.R = &ontologySnapshotR{}
+				}
+				foreign.R.Configuration = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadAssets allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (configurationL) LoadAssets(ctx context.Context, e boil.ContextExecutor, singular bool, maybeConfiguration interface{}, mods queries.Applicator) error {
@@ -672,6 +930,177 @@ func (configurationL) LoadAssets(ctx context.Context, e boil.ContextExecutor, si
 	return nil
 }
 
+// LoadSubscriptions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (configurationL) LoadSubscriptions(ctx context.Context, e boil.ContextExecutor, singular bool, maybeConfiguration interface{}, mods queries.Applicator) error {
+	var slice []*Configuration
+	var object *Configuration
+
+	if singular {
+		var ok bool
+		object, ok = maybeConfiguration.(*Configuration)
+		if !ok {
+			object = new(Configuration)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeConfiguration)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeConfiguration))
+			}
+		}
+	} else {
+		s, ok := maybeConfiguration.(*[]*Configuration)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeConfiguration)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeConfiguration))
+			}
+		}
+	}
+
+	args := make(map[interface{}]struct{})
+	if singular {
+		if object.R == nil {
+			object.R = &configurationR{}
+		}
+		args[object.ID] = struct{}{}
+	} else {
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &configurationR{}
+			}
+			args[obj.ID] = struct{}{}
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	argsSlice := make([]interface{}, len(args))
+	i := 0
+	for arg := range args {
+		argsSlice[i] = arg
+		i++
+	}
+
+	query := NewQuery(
+		qm.From(`open_bos.subscription`),
+		qm.WhereIn(`open_bos.subscription.configuration_id in ?`, argsSlice...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load subscription")
+	}
+
+	var resultSlice []*Subscription
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice subscription")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on subscription")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for subscription")
+	}
+
+	if len(subscriptionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Subscriptions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &subscriptionR{}
+			}
+			foreign.R.Configuration = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.ConfigurationID {
+				local.R.Subscriptions = append(local.R.Subscriptions, foreign)
+				if foreign.R == nil {
+					foreign.R = &subscriptionR{}
+				}
+				foreign.R.Configuration = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetOntologySnapshotG of the configuration to the related item.
+// Sets o.R.OntologySnapshot to related.
+// Adds o to related.R.Configuration.
+// Uses the global database handle.
+func (o *Configuration) SetOntologySnapshotG(ctx context.Context, insert bool, related *OntologySnapshot) error {
+	return o.SetOntologySnapshot(ctx, boil.GetContextDB(), insert, related)
+}
+
+// SetOntologySnapshot of the configuration to the related item.
+// Sets o.R.OntologySnapshot to related.
+// Adds o to related.R.Configuration.
+func (o *Configuration) SetOntologySnapshot(ctx context.Context, exec boil.ContextExecutor, insert bool, related *OntologySnapshot) error {
+	var err error
+
+	if insert {
+		related.ConfigurationID = o.ID
+
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	} else {
+		updateQuery := fmt.Sprintf(
+			"UPDATE \"open_bos\".\"ontology_snapshot\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, []string{"configuration_id"}),
+			strmangle.WhereClause("\"", "\"", 2, ontologySnapshotPrimaryKeyColumns),
+		)
+		values := []interface{}{o.ID, related.ConfigurationID}
+
+		if boil.IsDebug(ctx) {
+			writer := boil.DebugWriterFrom(ctx)
+			fmt.Fprintln(writer, updateQuery)
+			fmt.Fprintln(writer, values)
+		}
+		if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+			return errors.Wrap(err, "failed to update foreign table")
+		}
+
+		related.ConfigurationID = o.ID
+	}
+
+	if o.R == nil {
+		o.R = &configurationR{
+			OntologySnapshot: related,
+		}
+	} else {
+		o.R.OntologySnapshot = related
+	}
+
+	if related.R == nil {
+		related.R = &ontologySnapshotR{
+			Configuration: o,
+		}
+	} else {
+		related.R.Configuration = o
+	}
+	return nil
+}
+
 // AddAssetsG adds the given related objects to the existing relationships
 // of the configuration, optionally inserting them as new records.
 // Appends related to o.R.Assets.
@@ -734,6 +1163,68 @@ func (o *Configuration) AddAssets(ctx context.Context, exec boil.ContextExecutor
 	return nil
 }
 
+// AddSubscriptionsG adds the given related objects to the existing relationships
+// of the configuration, optionally inserting them as new records.
+// Appends related to o.R.Subscriptions.
+// Sets related.R.Configuration appropriately.
+// Uses the global database handle.
+func (o *Configuration) AddSubscriptionsG(ctx context.Context, insert bool, related ...*Subscription) error {
+	return o.AddSubscriptions(ctx, boil.GetContextDB(), insert, related...)
+}
+
+// AddSubscriptions adds the given related objects to the existing relationships
+// of the configuration, optionally inserting them as new records.
+// Appends related to o.R.Subscriptions.
+// Sets related.R.Configuration appropriately.
+func (o *Configuration) AddSubscriptions(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Subscription) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.ConfigurationID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"open_bos\".\"subscription\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"configuration_id"}),
+				strmangle.WhereClause("\"", "\"", 2, subscriptionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.ConfigurationID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &configurationR{
+			Subscriptions: related,
+		}
+	} else {
+		o.R.Subscriptions = append(o.R.Subscriptions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &subscriptionR{
+				Configuration: o,
+			}
+		} else {
+			rel.R.Configuration = o
+		}
+	}
+	return nil
+}
+
 // Configurations retrieves all the records using an executor.
 func Configurations(mods ...qm.QueryMod) configurationQuery {
 	mods = append(mods, qm.From("\"open_bos\".\"configuration\""))
@@ -26,6 +26,9 @@ type ElionaAttribute struct {
 	ID                  int64  `boil:"id" json:"id" toml:"id" yaml:"id"`
 	OpenbosDatapointID  int64  `boil:"openbos_datapoint_id" json:"openbos_datapoint_id" toml:"openbos_datapoint_id" yaml:"openbos_datapoint_id"`
 	ElionaAttributeName string `boil:"eliona_attribute_name" json:"eliona_attribute_name" toml:"eliona_attribute_name" yaml:"eliona_attribute_name"`
+	UnitID              string `boil:"unit_id" json:"unit_id" toml:"unit_id" yaml:"unit_id"`
+	UnitSymbol          string `boil:"unit_symbol" json:"unit_symbol" toml:"unit_symbol" yaml:"unit_symbol"`
+	Format              string `boil:"format" json:"format" toml:"format" yaml:"format"`
 
 	R *elionaAttributeR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L elionaAttributeL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -35,20 +38,32 @@ var ElionaAttributeColumns = struct {
 	ID                  string
 	OpenbosDatapointID  string
 	ElionaAttributeName string
+	UnitID              string
+	UnitSymbol          string
+	Format              string
 }{
 	ID:                  "id",
 	OpenbosDatapointID:  "openbos_datapoint_id",
 	ElionaAttributeName: "eliona_attribute_name",
+	UnitID:              "unit_id",
+	UnitSymbol:          "unit_symbol",
+	Format:              "format",
 }
 
 var ElionaAttributeTableColumns = struct {
 	ID                  string
 	OpenbosDatapointID  string
 	ElionaAttributeName string
+	UnitID              string
+	UnitSymbol          string
+	Format              string
 }{
 	ID:                  "eliona_attribute.id",
 	OpenbosDatapointID:  "eliona_attribute.openbos_datapoint_id",
 	ElionaAttributeName: "eliona_attribute.eliona_attribute_name",
+	UnitID:              "eliona_attribute.unit_id",
+	UnitSymbol:          "eliona_attribute.unit_symbol",
+	Format:              "eliona_attribute.format",
 }
 
 // Generated where
@@ -57,10 +72,16 @@ var ElionaAttributeWhere = struct {
 	ID                  whereHelperint64
 	OpenbosDatapointID  whereHelperint64
 	ElionaAttributeName whereHelperstring
+	UnitID              whereHelperstring
+	UnitSymbol          whereHelperstring
+	Format              whereHelperstring
 }{
 	ID:                  whereHelperint64{field: "\"open_bos\".\"eliona_attribute\".\"id\""},
 	OpenbosDatapointID:  whereHelperint64{field: "\"open_bos\".\"eliona_attribute\".\"openbos_datapoint_id\""},
 	ElionaAttributeName: whereHelperstring{field: "\"open_bos\".\"eliona_attribute\".\"eliona_attribute_name\""},
+	UnitID:              whereHelperstring{field: "\"open_bos\".\"eliona_attribute\".\"unit_id\""},
+	UnitSymbol:          whereHelperstring{field: "\"open_bos\".\"eliona_attribute\".\"unit_symbol\""},
+	Format:              whereHelperstring{field: "\"open_bos\".\"eliona_attribute\".\"format\""},
 }
 
 // ElionaAttributeRels is where relationship names are stored.
@@ -101,9 +122,9 @@ func (r *elionaAttributeR) GetAlarms() AlarmSlice {
 type elionaAttributeL struct{}
 
 var (
-	elionaAttributeAllColumns            = []string{"id", "openbos_datapoint_id", "eliona_attribute_name"}
+	elionaAttributeAllColumns            = []string{"id", "openbos_datapoint_id", "eliona_attribute_name", "unit_id", "unit_symbol", "format"}
 	elionaAttributeColumnsWithoutDefault = []string{"eliona_attribute_name"}
-	elionaAttributeColumnsWithDefault    = []string{"id", "openbos_datapoint_id"}
+	elionaAttributeColumnsWithDefault    = []string{"id", "openbos_datapoint_id", "unit_id", "unit_symbol", "format"}
 	elionaAttributePrimaryKeyColumns     = []string{"id"}
 	elionaAttributeGeneratedColumns      = []string{}
 )
@@ -18,16 +18,18 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"github.com/volatiletech/strmangle"
 )
 
 // OpenbosDatapoint is an object representing the database table.
 type OpenbosDatapoint struct {
-	ID         int64  `boil:"id" json:"id" toml:"id" yaml:"id"`
-	AssetID    int64  `boil:"asset_id" json:"asset_id" toml:"asset_id" yaml:"asset_id"`
-	Subtype    string `boil:"subtype" json:"subtype" toml:"subtype" yaml:"subtype"`
-	ProviderID string `boil:"provider_id" json:"provider_id" toml:"provider_id" yaml:"provider_id"`
-	Name       string `boil:"name" json:"name" toml:"name" yaml:"name"`
+	ID         int64             `boil:"id" json:"id" toml:"id" yaml:"id"`
+	AssetID    int64             `boil:"asset_id" json:"asset_id" toml:"asset_id" yaml:"asset_id"`
+	Subtype    string            `boil:"subtype" json:"subtype" toml:"subtype" yaml:"subtype"`
+	ProviderID string            `boil:"provider_id" json:"provider_id" toml:"provider_id" yaml:"provider_id"`
+	Name       string            `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Tags       types.StringArray `boil:"tags" json:"tags" toml:"tags" yaml:"tags"`
 
 	R *openbosDatapointR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L openbosDatapointL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -39,12 +41,14 @@ var OpenbosDatapointColumns = struct {
 	Subtype    string
 	ProviderID string
 	Name       string
+	Tags       string
 }{
 	ID:         "id",
 	AssetID:    "asset_id",
 	Subtype:    "subtype",
 	ProviderID: "provider_id",
 	Name:       "name",
+	Tags:       "tags",
 }
 
 var OpenbosDatapointTableColumns = struct {
@@ -53,12 +57,14 @@ var OpenbosDatapointTableColumns = struct {
 	Subtype    string
 	ProviderID string
 	Name       string
+	Tags       string
 }{
 	ID:         "openbos_datapoint.id",
 	AssetID:    "openbos_datapoint.asset_id",
 	Subtype:    "openbos_datapoint.subtype",
 	ProviderID: "openbos_datapoint.provider_id",
 	Name:       "openbos_datapoint.name",
+	Tags:       "openbos_datapoint.tags",
 }
 
 // Generated where
@@ -69,12 +75,14 @@ var OpenbosDatapointWhere = struct {
 	Subtype    whereHelperstring
 	ProviderID whereHelperstring
 	Name       whereHelperstring
+	Tags       whereHelpertypes_StringArray
 }{
 	ID:         whereHelperint64{field: "\"open_bos\".\"openbos_datapoint\".\"id\""},
 	AssetID:    whereHelperint64{field: "\"open_bos\".\"openbos_datapoint\".\"asset_id\""},
 	Subtype:    whereHelperstring{field: "\"open_bos\".\"openbos_datapoint\".\"subtype\""},
 	ProviderID: whereHelperstring{field: "\"open_bos\".\"openbos_datapoint\".\"provider_id\""},
 	Name:       whereHelperstring{field: "\"open_bos\".\"openbos_datapoint\".\"name\""},
+	Tags:       whereHelpertypes_StringArray{field: "\"open_bos\".\"openbos_datapoint\".\"tags\""},
 }
 
 // OpenbosDatapointRels is where relationship names are stored.
@@ -115,9 +123,9 @@ func (r *openbosDatapointR) GetElionaAttributes() ElionaAttributeSlice {
 type openbosDatapointL struct{}
 
 var (
-	openbosDatapointAllColumns            = []string{"id", "asset_id", "subtype", "provider_id", "name"}
+	openbosDatapointAllColumns            = []string{"id", "asset_id", "subtype", "provider_id", "name", "tags"}
 	openbosDatapointColumnsWithoutDefault = []string{"subtype", "provider_id", "name"}
-	openbosDatapointColumnsWithDefault    = []string{"id", "asset_id"}
+	openbosDatapointColumnsWithDefault    = []string{"id", "asset_id", "tags"}
 	openbosDatapointPrimaryKeyColumns     = []string{"id"}
 	openbosDatapointGeneratedColumns      = []string{}
 )
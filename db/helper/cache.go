@@ -0,0 +1,296 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package dbhelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	appmodel "open-bos/app/model"
+	"sync"
+	"sync/atomic"
+)
+
+// datapointCache keeps datapoint lookups of the live data, alarm and output
+// paths off the database. Datapoints that are not mapped are cached as well,
+// since the edge keeps sending data of assets excluded by the asset filter.
+// Entries are dropped whenever the ontology or the configuration changes.
+// The webhook secrets checked for each webhook call are kept alongside.
+type datapointCache struct {
+	mu          sync.RWMutex
+	byProvider  map[providerKey]cachedDatapoint
+	byAttribute map[attributeKey]cachedDatapoint
+	secrets     map[int64]string // Webhook secrets by configuration ID.
+
+	// Generations are incremented by invalidate. A value loaded while its
+	// generation changed might already be outdated and is not cached.
+	generations    map[int64]uint64 // Per configuration.
+	allGenerations uint64           // Of all configurations, for keys not tied to one.
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	perConfig sync.Map // *lookupCounts by configuration ID.
+}
+
+type lookupCounts struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cacheKey interface {
+	comparable
+	// config returns the configuration the entry of the key belongs to, false
+	// if that is only known once the entry is loaded.
+	config() (int64, bool)
+}
+
+type providerKey struct {
+	configID   int64
+	providerID string
+}
+
+func (k providerKey) config() (int64, bool) {
+	return k.configID, true
+}
+
+type attributeKey struct {
+	assetID       int32
+	attributeName string
+}
+
+func (k attributeKey) config() (int64, bool) {
+	return 0, false
+}
+
+type cachedDatapoint struct {
+	configID  int64
+	datapoint appmodel.Datapoint
+	found     bool
+}
+
+// CacheStats counts the lookups answered from the cache and those that needed
+// the database.
+type CacheStats struct {
+	Entries int
+	Hits    int64
+	Misses  int64
+}
+
+func (s CacheStats) String() string {
+	return fmt.Sprintf("%d entries, %d hits, %d misses", s.Entries, s.Hits, s.Misses)
+}
+
+var datapoints = newDatapointCache()
+
+func newDatapointCache() *datapointCache {
+	return &datapointCache{
+		byProvider:  make(map[providerKey]cachedDatapoint),
+		byAttribute: make(map[attributeKey]cachedDatapoint),
+		secrets:     make(map[int64]string),
+		generations: make(map[int64]uint64),
+	}
+}
+
+// generation returns the generation entries of the configuration are loaded
+// in. Must be called with the lock held.
+func (c *datapointCache) generation(configID int64, known bool) uint64 {
+	if !known {
+		return c.allGenerations
+	}
+	return c.generations[configID]
+}
+
+// get returns the datapoint from the cache, or loads and caches it on a miss.
+func get[K cacheKey](c *datapointCache, m map[K]cachedDatapoint, key K, load func() (appmodel.Datapoint, error)) (appmodel.Datapoint, error) {
+	configID, known := key.config()
+	c.mu.RLock()
+	entry, ok := m[key]
+	generation := c.generation(configID, known)
+	c.mu.RUnlock()
+	if ok {
+		if !known && entry.found {
+			configID, known = entry.configID, true
+		}
+		c.count(configID, known, true)
+		if !entry.found {
+			return appmodel.Datapoint{}, fmt.Errorf("datapoint not mapped (cached): %w", ErrNotFound)
+		}
+		return entry.datapoint, nil
+	}
+
+	datapoint, err := load()
+	entry = cachedDatapoint{datapoint: datapoint, found: err == nil}
+	if entry.found {
+		entry.configID = datapoint.Asset.Config.Id
+	}
+	if !known && entry.found {
+		c.count(entry.configID, true, false)
+	} else {
+		c.count(configID, known, false)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return appmodel.Datapoint{}, err
+	}
+	c.mu.Lock()
+	if c.generation(configID, known) == generation {
+		m[key] = entry
+	}
+	c.mu.Unlock()
+	return datapoint, err
+}
+
+// peek returns the datapoint if the cache knows it, without loading it on a
+// miss. Peeks are not counted as lookups.
+func peek[K cacheKey](c *datapointCache, m map[K]cachedDatapoint, key K) (appmodel.Datapoint, bool) {
+	c.mu.RLock()
+	entry, ok := m[key]
+	c.mu.RUnlock()
+	if !ok || !entry.found {
+		return appmodel.Datapoint{}, false
+	}
+	return entry.datapoint, true
+}
+
+// count records a lookup in the totals and, if known, for the configuration.
+func (c *datapointCache) count(configID int64, known bool, hit bool) {
+	var counts *lookupCounts
+	if known {
+		value, _ := c.perConfig.LoadOrStore(configID, &lookupCounts{})
+		counts = value.(*lookupCounts)
+	}
+	if hit {
+		c.hits.Add(1)
+		if counts != nil {
+			counts.hits.Add(1)
+		}
+		return
+	}
+	c.misses.Add(1)
+	if counts != nil {
+		counts.misses.Add(1)
+	}
+}
+
+// webhookSecret returns the webhook secret of the configuration from the
+// cache, or loads and caches it on a miss. Unknown configurations are not
+// cached, as the callers of the webhooks choose the configuration ID.
+func (c *datapointCache) webhookSecret(configID int64, load func() (string, error)) (string, error) {
+	c.mu.RLock()
+	secret, ok := c.secrets[configID]
+	generation := c.generation(configID, true)
+	c.mu.RUnlock()
+	if ok {
+		return secret, nil
+	}
+
+	secret, err := load()
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.generation(configID, true) == generation {
+		c.secrets[configID] = secret
+	}
+	c.mu.Unlock()
+	return secret, nil
+}
+
+// invalidate drops all entries of the configuration, including the datapoints
+// that were not found, as they might belong to it now.
+func (c *datapointCache) invalidate(configID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[configID]++
+	c.allGenerations++
+	delete(c.secrets, configID)
+	for key, entry := range c.byProvider {
+		if key.configID == configID || entry.configID == configID {
+			delete(c.byProvider, key)
+		}
+	}
+	for key, entry := range c.byAttribute {
+		if !entry.found || entry.configID == configID {
+			delete(c.byAttribute, key)
+		}
+	}
+}
+
+func (c *datapointCache) stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CacheStats{
+		Entries: len(c.byProvider) + len(c.byAttribute),
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
+
+// configStats returns the statistics of the entries and lookups of the
+// configuration. Lookups of datapoints no configuration maps are only part of
+// the totals.
+func (c *datapointCache) configStats(configID int64) CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var stats CacheStats
+	for key := range c.byProvider {
+		if key.configID == configID {
+			stats.Entries++
+		}
+	}
+	for _, entry := range c.byAttribute {
+		if entry.found && entry.configID == configID {
+			stats.Entries++
+		}
+	}
+	if value, ok := c.perConfig.Load(configID); ok {
+		counts := value.(*lookupCounts)
+		stats.Hits = counts.hits.Load()
+		stats.Misses = counts.misses.Load()
+	}
+	return stats
+}
+
+// InvalidateDatapointCache drops the cached datapoints of the configuration.
+// Must be called after the mapping of the configuration changed.
+func InvalidateDatapointCache(configID int64) {
+	datapoints.invalidate(configID)
+}
+
+// GetWebhookSecret returns the secret the webhook URLs of the configuration
+// contain. Lookups are cached until the configuration changes.
+func GetWebhookSecret(ctx context.Context, configID int64) (string, error) {
+	return datapoints.webhookSecret(configID, func() (string, error) {
+		config, err := GetConfig(ctx, configID)
+		if err != nil {
+			return "", err
+		}
+		return config.WebhookSecret, nil
+	})
+}
+
+// DatapointCacheStats returns the statistics of the datapoint cache.
+func DatapointCacheStats() CacheStats {
+	return datapoints.stats()
+}
+
+// ConfigDatapointCacheStats returns the statistics of the datapoint cache
+// concerning the configuration.
+func ConfigDatapointCacheStats(configID int64) CacheStats {
+	return datapoints.configStats(configID)
+}
@@ -0,0 +1,166 @@
+package dbhelper
+
+import (
+	appmodel "open-bos/app/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDatapoint(configID int64, providerID string) appmodel.Datapoint {
+	return appmodel.Datapoint{
+		ProviderID: providerID,
+		Asset:      &appmodel.Asset{Config: appmodel.Configuration{Id: configID}},
+	}
+}
+
+func TestDatapointCacheHitsAndMisses(t *testing.T) {
+	c := newDatapointCache()
+	loads := 0
+	load := func() (appmodel.Datapoint, error) {
+		loads++
+		return testDatapoint(1, "dp-1"), nil
+	}
+
+	for i := 0; i < 3; i++ {
+		datapoint, err := get(c, c.byProvider, providerKey{configID: 1, providerID: "dp-1"}, load)
+		assert.NoError(t, err)
+		assert.Equal(t, "dp-1", datapoint.ProviderID)
+	}
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, CacheStats{Entries: 1, Hits: 2, Misses: 1}, c.stats())
+}
+
+func TestDatapointCacheRemembersMissingDatapoints(t *testing.T) {
+	c := newDatapointCache()
+	loads := 0
+	load := func() (appmodel.Datapoint, error) {
+		loads++
+		return appmodel.Datapoint{}, ErrNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := get(c, c.byAttribute, attributeKey{assetID: 5, attributeName: "temperature"}, load)
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Equal(t, 1, loads)
+
+	// The datapoint might have been mapped by any configuration.
+	c.invalidate(2)
+	_, err := get(c, c.byAttribute, attributeKey{assetID: 5, attributeName: "temperature"}, load)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 2, loads)
+}
+
+func TestDatapointCacheInvalidatesConfig(t *testing.T) {
+	c := newDatapointCache()
+	for _, configID := range []int64{1, 2} {
+		_, err := get(c, c.byProvider, providerKey{configID: configID, providerID: "dp"}, func() (appmodel.Datapoint, error) {
+			return testDatapoint(configID, "dp"), nil
+		})
+		assert.NoError(t, err)
+		_, err = get(c, c.byAttribute, attributeKey{assetID: int32(configID), attributeName: "a"}, func() (appmodel.Datapoint, error) {
+			return testDatapoint(configID, "dp"), nil
+		})
+		assert.NoError(t, err)
+	}
+
+	c.invalidate(1)
+	assert.NotContains(t, c.byProvider, providerKey{configID: 1, providerID: "dp"})
+	assert.NotContains(t, c.byAttribute, attributeKey{assetID: 1, attributeName: "a"})
+	assert.Contains(t, c.byProvider, providerKey{configID: 2, providerID: "dp"})
+	assert.Contains(t, c.byAttribute, attributeKey{assetID: 2, attributeName: "a"})
+}
+
+func TestWebhookSecretCache(t *testing.T) {
+	c := newDatapointCache()
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "s3cret", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		secret, err := c.webhookSecret(1, load)
+		assert.NoError(t, err)
+		assert.Equal(t, "s3cret", secret)
+	}
+	assert.Equal(t, 1, loads)
+
+	c.invalidate(1)
+	_, err := c.webhookSecret(1, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// Unknown configurations are not cached.
+	for i := 0; i < 2; i++ {
+		_, err := c.webhookSecret(2, func() (string, error) { return "", ErrNotFound })
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Len(t, c.secrets, 1)
+}
+
+func TestDatapointCacheDiscardsLoadsRacingInvalidation(t *testing.T) {
+	c := newDatapointCache()
+
+	// The mapping changes while the datapoint is loaded.
+	_, err := get(c, c.byProvider, providerKey{configID: 1, providerID: "dp"}, func() (appmodel.Datapoint, error) {
+		c.invalidate(1)
+		return appmodel.Datapoint{}, ErrNotFound
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotContains(t, c.byProvider, providerKey{configID: 1, providerID: "dp"})
+
+	_, err = get(c, c.byAttribute, attributeKey{assetID: 5, attributeName: "a"}, func() (appmodel.Datapoint, error) {
+		c.invalidate(2)
+		return testDatapoint(2, "dp"), nil
+	})
+	assert.NoError(t, err)
+	assert.NotContains(t, c.byAttribute, attributeKey{assetID: 5, attributeName: "a"})
+
+	// Invalidating another configuration does not affect the load.
+	_, err = get(c, c.byProvider, providerKey{configID: 1, providerID: "dp"}, func() (appmodel.Datapoint, error) {
+		c.invalidate(2)
+		return testDatapoint(1, "dp"), nil
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, c.byProvider, providerKey{configID: 1, providerID: "dp"})
+}
+
+func TestDatapointCacheStatsPerConfig(t *testing.T) {
+	c := newDatapointCache()
+	for i := 0; i < 2; i++ {
+		_, err := get(c, c.byProvider, providerKey{configID: 1, providerID: "dp"}, func() (appmodel.Datapoint, error) {
+			return testDatapoint(1, "dp"), nil
+		})
+		assert.NoError(t, err)
+		_, err = get(c, c.byAttribute, attributeKey{assetID: 5, attributeName: "a"}, func() (appmodel.Datapoint, error) {
+			return testDatapoint(2, "dp"), nil
+		})
+		assert.NoError(t, err)
+		_, err = get(c, c.byAttribute, attributeKey{assetID: 6, attributeName: "a"}, func() (appmodel.Datapoint, error) {
+			return appmodel.Datapoint{}, ErrNotFound
+		})
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+
+	assert.Equal(t, CacheStats{Entries: 1, Hits: 1, Misses: 1}, c.configStats(1))
+	assert.Equal(t, CacheStats{Entries: 1, Hits: 1, Misses: 1}, c.configStats(2))
+	assert.Equal(t, CacheStats{Entries: 3, Hits: 3, Misses: 3}, c.stats())
+}
+
+func TestDatapointCachePeekDoesNotLoad(t *testing.T) {
+	c := newDatapointCache()
+	key := providerKey{configID: 1, providerID: "dp-1"}
+	_, ok := peek(c, c.byProvider, key)
+	assert.False(t, ok)
+
+	_, err := get(c, c.byProvider, key, func() (appmodel.Datapoint, error) {
+		return testDatapoint(1, "dp-1"), nil
+	})
+	assert.NoError(t, err)
+	datapoint, ok := peek(c, c.byProvider, key)
+	assert.True(t, ok)
+	assert.Equal(t, "dp-1", datapoint.ProviderID)
+	assert.Equal(t, CacheStats{Entries: 1, Hits: 0, Misses: 1}, c.stats())
+}
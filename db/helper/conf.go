@@ -21,6 +21,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 
@@ -37,6 +40,30 @@ import (
 var ErrBadRequest = errors.New("bad request")
 var ErrNotFound = errors.New("not found")
 
+// configRemovedHandlers release what other packages keep for a configuration
+// once it is deleted or deactivated.
+var configRemovedHandlers struct {
+	sync.Mutex
+	handlers []func(configID int64)
+}
+
+// OnConfigRemoved registers a handler that is called after a configuration was
+// deleted or deactivated.
+func OnConfigRemoved(handler func(configID int64)) {
+	configRemovedHandlers.Lock()
+	defer configRemovedHandlers.Unlock()
+	configRemovedHandlers.handlers = append(configRemovedHandlers.handlers, handler)
+}
+
+func configRemoved(configID int64) {
+	configRemovedHandlers.Lock()
+	handlers := slices.Clone(configRemovedHandlers.handlers)
+	configRemovedHandlers.Unlock()
+	for _, handler := range handlers {
+		handler(configID)
+	}
+}
+
 func InsertConfig(ctx context.Context, config appmodel.Configuration) (appmodel.Configuration, error) {
 	dbConfig, err := toDbConfig(ctx, config)
 	if err != nil {
@@ -56,6 +83,8 @@ func UpsertConfig(ctx context.Context, config appmodel.Configuration) (appmodel.
 	if err := dbConfig.UpsertG(ctx, true, []string{"id"}, boil.Blacklist("id"), boil.Infer()); err != nil {
 		return appmodel.Configuration{}, fmt.Errorf("inserting DB config: %v", err)
 	}
+	// Cached datapoints carry the configuration.
+	InvalidateDatapointCache(config.Id)
 	return config, nil
 }
 
@@ -105,6 +134,8 @@ func DeleteConfig(ctx context.Context, configID int64) error {
 	if count == 0 {
 		return ErrNotFound
 	}
+	InvalidateDatapointCache(configID)
+	configRemoved(configID)
 	return nil
 }
 
@@ -114,10 +145,57 @@ func toDbConfig(ctx context.Context, appConfig appmodel.Configuration) (dbConfig
 	dbConfig.ClientSecret = appConfig.ClientSecret
 	dbConfig.OntologyVersion = appConfig.OntologyVersion
 	dbConfig.AppPublicAPIURL = appConfig.AppPublicAPIURL
+	dbConfig.WebhookSecret = appConfig.WebhookSecret
+	dbConfig.BaseURL = appConfig.BaseURL
+	dbConfig.TokenURL = appConfig.TokenURL
+	dbConfig.Scope = appConfig.Scope
 
 	dbConfig.ID = appConfig.Id
 	dbConfig.RefreshInterval = appConfig.RefreshInterval
 	dbConfig.RequestTimeout = appConfig.RequestTimeout
+	dbConfig.MaxRetries = appConfig.MaxRetries
+	dbConfig.RetryBaseDelay = appConfig.RetryBaseDelay
+	dbConfig.RetryMaxDelay = appConfig.RetryMaxDelay
+	dbConfig.DeletionPolicy = string(appConfig.DeletionPolicy)
+	dbConfig.BadQualityPolicy = string(appConfig.BadQualityPolicy)
+	dbConfig.ClampFutureTimestamps = appConfig.ClampFutureTimestamps
+	unitPreferences := appConfig.UnitPreferences
+	if unitPreferences == nil {
+		unitPreferences = map[string]string{} // Stored as an empty object rather than null.
+	}
+	up, err := json.Marshal(unitPreferences)
+	if err != nil {
+		return dbgen.Configuration{}, fmt.Errorf("marshalling unitPreferences: %v", err)
+	}
+	dbConfig.UnitPreferences = up
+	unitConversions := appConfig.UnitConversions
+	if unitConversions == nil {
+		unitConversions = []appmodel.UnitConversion{}
+	}
+	uc, err := json.Marshal(unitConversions)
+	if err != nil {
+		return dbgen.Configuration{}, fmt.Errorf("marshalling unitConversions: %v", err)
+	}
+	dbConfig.UnitConversions = uc
+	dbConfig.TagPrefix = appConfig.TagPrefix
+	tagAllowList := appConfig.TagAllowList
+	if tagAllowList == nil {
+		tagAllowList = []string{}
+	}
+	tal, err := json.Marshal(tagAllowList)
+	if err != nil {
+		return dbgen.Configuration{}, fmt.Errorf("marshalling tagAllowList: %v", err)
+	}
+	dbConfig.TagAllowList = tal
+	iconMapping := appConfig.IconMapping
+	if iconMapping == nil {
+		iconMapping = map[string]string{}
+	}
+	im, err := json.Marshal(iconMapping)
+	if err != nil {
+		return dbgen.Configuration{}, fmt.Errorf("marshalling iconMapping: %v", err)
+	}
+	dbConfig.IconMapping = im
 	af, err := json.Marshal(appConfig.AssetFilter)
 	if err != nil {
 		return dbgen.Configuration{}, fmt.Errorf("marshalling assetFilter: %v", err)
@@ -144,11 +222,36 @@ func toAppConfig(dbConfig *dbgen.Configuration) (appConfig appmodel.Configuratio
 	appConfig.ClientSecret = dbConfig.ClientSecret
 	appConfig.OntologyVersion = dbConfig.OntologyVersion
 	appConfig.AppPublicAPIURL = dbConfig.AppPublicAPIURL
+	appConfig.WebhookSecret = dbConfig.WebhookSecret
+	appConfig.BaseURL = dbConfig.BaseURL
+	appConfig.TokenURL = dbConfig.TokenURL
+	appConfig.Scope = dbConfig.Scope
 
 	appConfig.Id = dbConfig.ID
 	appConfig.Enable = dbConfig.Enable
 	appConfig.RefreshInterval = dbConfig.RefreshInterval
 	appConfig.RequestTimeout = dbConfig.RequestTimeout
+	appConfig.MaxRetries = dbConfig.MaxRetries
+	appConfig.RetryBaseDelay = dbConfig.RetryBaseDelay
+	appConfig.RetryMaxDelay = dbConfig.RetryMaxDelay
+	appConfig.DeletionPolicy = appmodel.DeletionPolicy(dbConfig.DeletionPolicy)
+	appConfig.BadQualityPolicy = appmodel.BadQualityPolicy(dbConfig.BadQualityPolicy)
+	appConfig.ClampFutureTimestamps = dbConfig.ClampFutureTimestamps
+	if err := json.Unmarshal(dbConfig.UnitPreferences, &appConfig.UnitPreferences); err != nil {
+		return appmodel.Configuration{}, fmt.Errorf("unmarshalling unitPreferences: %v", err)
+	}
+	if err := json.Unmarshal(dbConfig.UnitConversions, &appConfig.UnitConversions); err != nil {
+		return appmodel.Configuration{}, fmt.Errorf("unmarshalling unitConversions: %v", err)
+	}
+	appConfig.TagPrefix = dbConfig.TagPrefix
+	if err := json.Unmarshal(dbConfig.TagAllowList, &appConfig.TagAllowList); err != nil {
+		return appmodel.Configuration{}, fmt.Errorf("unmarshalling tagAllowList: %v", err)
+	}
+	if err := json.Unmarshal(dbConfig.IconMapping, &appConfig.IconMapping); err != nil {
+		return appmodel.Configuration{}, fmt.Errorf("unmarshalling iconMapping: %v", err)
+	}
+	// Mappings stored by earlier versions are not normalized yet.
+	appConfig.IconMapping = appmodel.NormalizeIconMapping(appConfig.IconMapping)
 	var af [][]appmodel.FilterRule
 	if err := json.Unmarshal(dbConfig.AssetFilter, &af); err != nil {
 		return appmodel.Configuration{}, fmt.Errorf("unmarshalling assetFilter: %v", err)
@@ -177,11 +280,15 @@ func GetConfigs(ctx context.Context) ([]appmodel.Configuration, error) {
 }
 
 func SetConfigActiveState(ctx context.Context, config appmodel.Configuration, state bool) (int64, error) {
-	return dbgen.Configurations(
+	count, err := dbgen.Configurations(
 		dbgen.ConfigurationWhere.ID.EQ(config.Id),
 	).UpdateAllG(ctx, dbgen.M{
 		dbgen.ConfigurationColumns.Active: state,
 	})
+	if err == nil && !state {
+		configRemoved(config.Id)
+	}
+	return count, err
 }
 
 func SetAllConfigsInactive(ctx context.Context) (int64, error) {
@@ -224,6 +331,7 @@ func InsertAssetAttributes(ctx context.Context, assetId int64, datapoints []appm
 			Subtype:    datapoint.Subtype,
 			ProviderID: datapoint.ProviderID,
 			Name:       datapoint.AttributeNamePrefix,
+			Tags:       datapoint.Tags,
 		}
 
 		if err := dbDatapoint.InsertG(ctx, boil.Infer()); err != nil {
@@ -235,6 +343,9 @@ func InsertAssetAttributes(ctx context.Context, assetId int64, datapoints []appm
 			dbAttribute := dbgen.ElionaAttribute{
 				OpenbosDatapointID:  dbDatapoint.ID,
 				ElionaAttributeName: attribute.Name,
+				UnitID:              attribute.UnitID,
+				UnitSymbol:          attribute.UnitSymbol,
+				Format:              attribute.Format,
 			}
 
 			if err := dbAttribute.InsertG(ctx, boil.Infer()); err != nil {
@@ -246,6 +357,16 @@ func InsertAssetAttributes(ctx context.Context, assetId int64, datapoints []appm
 	return nil
 }
 
+func toAppAttribute(dbAttribute *dbgen.ElionaAttribute) appmodel.Attribute {
+	return appmodel.Attribute{
+		ID:         dbAttribute.ID,
+		Name:       dbAttribute.ElionaAttributeName,
+		UnitID:     dbAttribute.UnitID,
+		UnitSymbol: dbAttribute.UnitSymbol,
+		Format:     dbAttribute.Format,
+	}
+}
+
 func toAppAsset(dbAsset dbgen.Asset, config appmodel.Configuration) appmodel.Asset {
 	return appmodel.Asset{
 		ID:            dbAsset.ID,
@@ -254,6 +375,7 @@ func toAppAsset(dbAsset dbgen.Asset, config appmodel.Configuration) appmodel.Ass
 		GlobalAssetID: dbAsset.GlobalAssetID,
 		ProviderID:    dbAsset.ProviderID,
 		AssetID:       dbAsset.AssetID.Int32,
+		Stale:         dbAsset.Stale,
 	}
 }
 
@@ -282,7 +404,22 @@ func GetAssetById(assetId int32) (appmodel.Asset, error) {
 	return toAppAsset(*asset, config), nil
 }
 
+// GetDatapointById returns the datapoint with the given OpenBOS ID. Lookups are
+// cached.
 func GetDatapointById(providerDatapointID string, configID int64) (appmodel.Datapoint, error) {
+	key := providerKey{configID: configID, providerID: providerDatapointID}
+	return get(datapoints, datapoints.byProvider, key, func() (appmodel.Datapoint, error) {
+		return getDatapointById(providerDatapointID, configID)
+	})
+}
+
+// PeekDatapointById returns the datapoint with the given OpenBOS ID if it is
+// cached, without querying the database otherwise.
+func PeekDatapointById(providerDatapointID string, configID int64) (appmodel.Datapoint, bool) {
+	return peek(datapoints, datapoints.byProvider, providerKey{configID: configID, providerID: providerDatapointID})
+}
+
+func getDatapointById(providerDatapointID string, configID int64) (appmodel.Datapoint, error) {
 	ctx := context.Background()
 
 	datapointTable := "open_bos.openbos_datapoint"
@@ -314,10 +451,7 @@ func GetDatapointById(providerDatapointID string, configID int64) (appmodel.Data
 	// Map attributes to the appmodel structure
 	var appAttributes []appmodel.Attribute
 	for _, attr := range attributes {
-		appAttributes = append(appAttributes, appmodel.Attribute{
-			ID:   attr.ID,
-			Name: attr.ElionaAttributeName,
-		})
+		appAttributes = append(appAttributes, toAppAttribute(attr))
 	}
 
 	// Fetch the associated asset
@@ -345,10 +479,20 @@ func GetDatapointById(providerDatapointID string, configID int64) (appmodel.Data
 		Asset:               &appAsset,
 		AttributeNamePrefix: datapoint.Name,
 		Attributes:          appAttributes,
+		Tags:                datapoint.Tags,
 	}, nil
 }
 
+// GetDatapointByAttributeName returns the datapoint the attribute of the
+// Eliona asset belongs to. Lookups are cached.
 func GetDatapointByAttributeName(assetID int32, attributeName string) (appmodel.Datapoint, error) {
+	key := attributeKey{assetID: assetID, attributeName: attributeName}
+	return get(datapoints, datapoints.byAttribute, key, func() (appmodel.Datapoint, error) {
+		return getDatapointByAttributeName(assetID, attributeName)
+	})
+}
+
+func getDatapointByAttributeName(assetID int32, attributeName string) (appmodel.Datapoint, error) {
 	ctx := context.Background()
 
 	// Define table names for readability
@@ -389,10 +533,7 @@ func GetDatapointByAttributeName(assetID int32, attributeName string) (appmodel.
 	// Map attributes to appmodel.Attribute
 	var appAttributes []appmodel.Attribute
 	for _, attr := range relatedAttributes {
-		appAttributes = append(appAttributes, appmodel.Attribute{
-			ID:   attr.ID,
-			Name: attr.ElionaAttributeName,
-		})
+		appAttributes = append(appAttributes, toAppAttribute(attr))
 	}
 
 	// Retrieve the associated asset
@@ -422,6 +563,7 @@ func GetDatapointByAttributeName(assetID int32, attributeName string) (appmodel.
 		Asset:               &appAsset,
 		AttributeNamePrefix: datapoint.Name,
 		Attributes:          appAttributes,
+		Tags:                datapoint.Tags,
 	}, nil
 }
 
@@ -500,3 +642,269 @@ func GetAlarmByElionaID(elionaAlarmID int32) (appmodel.Alarm, error) {
 		OpenBOSAlarmID: dbAlarm.OpenbosAlarmID,
 	}, nil
 }
+
+func GetOntologySnapshot(ctx context.Context, configID int64) ([]byte, error) {
+	snapshot, err := dbgen.OntologySnapshots(
+		dbgen.OntologySnapshotWhere.ConfigurationID.EQ(configID),
+	).OneG(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching ontology snapshot: %v", err)
+	}
+	return snapshot.Ontology, nil
+}
+
+// DeleteOntologySnapshot makes the next synchronization process the complete
+// ontology.
+func DeleteOntologySnapshot(ctx context.Context, configID int64) error {
+	if _, err := dbgen.OntologySnapshots(
+		dbgen.OntologySnapshotWhere.ConfigurationID.EQ(configID),
+	).DeleteAllG(ctx); err != nil {
+		return fmt.Errorf("deleting ontology snapshot: %v", err)
+	}
+	return nil
+}
+
+func SaveOntologySnapshot(ctx context.Context, configID int64, version int32, ontology []byte) error {
+	snapshot := dbgen.OntologySnapshot{
+		ConfigurationID: configID,
+		Version:         version,
+		Ontology:        ontology,
+		UpdatedAt:       time.Now(),
+	}
+	if err := snapshot.UpsertG(ctx, true, []string{dbgen.OntologySnapshotColumns.ConfigurationID}, boil.Infer(), boil.Infer()); err != nil {
+		return fmt.Errorf("upserting ontology snapshot: %v", err)
+	}
+	return nil
+}
+
+func GetAsset(ctx context.Context, config appmodel.Configuration, projId string, globalAssetID string) (appmodel.Asset, error) {
+	dbAsset, err := dbgen.Assets(
+		dbgen.AssetWhere.ConfigurationID.EQ(config.Id),
+		dbgen.AssetWhere.ProjectID.EQ(projId),
+		dbgen.AssetWhere.GlobalAssetID.EQ(globalAssetID),
+	).OneG(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return appmodel.Asset{}, ErrNotFound
+	}
+	if err != nil {
+		return appmodel.Asset{}, fmt.Errorf("fetching asset: %v", err)
+	}
+	return toAppAsset(*dbAsset, config), nil
+}
+
+// SyncDatapoint makes the datapoint belong to the asset and brings its
+// attributes in line. Attributes that remain keep their ID, so that alarms
+// referencing them are preserved.
+func SyncDatapoint(ctx context.Context, assetID int64, datapoint appmodel.Datapoint) error {
+	dbDatapoint, err := dbgen.OpenbosDatapoints(
+		dbgen.OpenbosDatapointWhere.ProviderID.EQ(datapoint.ProviderID),
+	).OneG(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return InsertAssetAttributes(ctx, assetID, []appmodel.Datapoint{datapoint})
+	}
+	if err != nil {
+		return fmt.Errorf("fetching datapoint %v: %v", datapoint.ProviderID, err)
+	}
+
+	dbDatapoint.AssetID = assetID
+	dbDatapoint.Subtype = datapoint.Subtype
+	dbDatapoint.Name = datapoint.AttributeNamePrefix
+	dbDatapoint.Tags = datapoint.Tags
+	if _, err := dbDatapoint.UpdateG(ctx, boil.Infer()); err != nil {
+		return fmt.Errorf("updating datapoint %v: %v", datapoint.ProviderID, err)
+	}
+
+	dbAttributes, err := dbgen.ElionaAttributes(
+		dbgen.ElionaAttributeWhere.OpenbosDatapointID.EQ(dbDatapoint.ID),
+	).AllG(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching attributes for datapoint %v: %v", datapoint.ProviderID, err)
+	}
+	existing := make(map[string]bool)
+	for _, dbAttribute := range dbAttributes {
+		i := slices.IndexFunc(datapoint.Attributes, func(a appmodel.Attribute) bool { return a.Name == dbAttribute.ElionaAttributeName })
+		if i >= 0 {
+			existing[dbAttribute.ElionaAttributeName] = true
+			if attribute := datapoint.Attributes[i]; dbAttribute.UnitID != attribute.UnitID || dbAttribute.UnitSymbol != attribute.UnitSymbol || dbAttribute.Format != attribute.Format {
+				dbAttribute.UnitID = attribute.UnitID
+				dbAttribute.UnitSymbol = attribute.UnitSymbol
+				dbAttribute.Format = attribute.Format
+				if _, err := dbAttribute.UpdateG(ctx, boil.Infer()); err != nil {
+					return fmt.Errorf("updating unit and format of attribute %v of datapoint %v: %v", dbAttribute.ElionaAttributeName, datapoint.ProviderID, err)
+				}
+			}
+			continue
+		}
+		if _, err := dbAttribute.DeleteG(ctx); err != nil {
+			return fmt.Errorf("deleting attribute %v of datapoint %v: %v", dbAttribute.ElionaAttributeName, datapoint.ProviderID, err)
+		}
+	}
+	for _, attribute := range datapoint.Attributes {
+		if existing[attribute.Name] {
+			continue
+		}
+		dbAttribute := dbgen.ElionaAttribute{
+			OpenbosDatapointID:  dbDatapoint.ID,
+			ElionaAttributeName: attribute.Name,
+			UnitID:              attribute.UnitID,
+			UnitSymbol:          attribute.UnitSymbol,
+			Format:              attribute.Format,
+		}
+		if err := dbAttribute.InsertG(ctx, boil.Infer()); err != nil {
+			return fmt.Errorf("inserting attribute %+v for datapoint %v: %v", attribute, datapoint.ProviderID, err)
+		}
+	}
+	return nil
+}
+
+func DeleteDatapoints(ctx context.Context, configID int64, providerIDs []string) error {
+	if len(providerIDs) == 0 {
+		return nil
+	}
+	if _, err := dbgen.OpenbosDatapoints(
+		qm.Where("asset_id in (select id from open_bos.asset where configuration_id = ?)", configID),
+		dbgen.OpenbosDatapointWhere.ProviderID.IN(providerIDs),
+	).DeleteAllG(ctx); err != nil {
+		return fmt.Errorf("deleting datapoints: %v", err)
+	}
+	return nil
+}
+
+func GetAssets(ctx context.Context, config appmodel.Configuration) ([]appmodel.Asset, error) {
+	dbAssets, err := dbgen.Assets(
+		dbgen.AssetWhere.ConfigurationID.EQ(config.Id),
+	).AllG(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching assets: %v", err)
+	}
+	var assets []appmodel.Asset
+	for _, dbAsset := range dbAssets {
+		assets = append(assets, toAppAsset(*dbAsset, config))
+	}
+	return assets, nil
+}
+
+// DeleteAsset forgets the mapping of an asset together with its datapoints,
+// attributes and alarms.
+func DeleteAsset(ctx context.Context, assetID int64) error {
+	if _, err := dbgen.Assets(
+		dbgen.AssetWhere.ID.EQ(assetID),
+	).DeleteAllG(ctx); err != nil {
+		return fmt.Errorf("deleting asset: %v", err)
+	}
+	return nil
+}
+
+func SetAssetStale(ctx context.Context, assetID int64, stale bool) error {
+	if _, err := dbgen.Assets(
+		dbgen.AssetWhere.ID.EQ(assetID),
+	).UpdateAllG(ctx, dbgen.M{
+		dbgen.AssetColumns.Stale: stale,
+	}); err != nil {
+		return fmt.Errorf("updating asset: %v", err)
+	}
+	return nil
+}
+
+func GetDatapointProviderIDs(ctx context.Context, configID int64) ([]string, error) {
+	dbDatapoints, err := dbgen.OpenbosDatapoints(
+		qm.Select(dbgen.OpenbosDatapointColumns.ProviderID),
+		qm.Where("asset_id in (select id from open_bos.asset where configuration_id = ?)", configID),
+	).AllG(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching datapoints: %v", err)
+	}
+	var providerIDs []string
+	for _, dbDatapoint := range dbDatapoints {
+		providerIDs = append(providerIDs, dbDatapoint.ProviderID)
+	}
+	return providerIDs, nil
+}
+
+// DeleteAssetDatapointsExcept forgets all datapoints of the asset apart from the
+// given ones.
+func DeleteAssetDatapointsExcept(ctx context.Context, assetID int64, providerIDs []string) error {
+	mods := []qm.QueryMod{
+		dbgen.OpenbosDatapointWhere.AssetID.EQ(assetID),
+	}
+	if len(providerIDs) > 0 {
+		mods = append(mods, dbgen.OpenbosDatapointWhere.ProviderID.NIN(providerIDs))
+	}
+	if _, err := dbgen.OpenbosDatapoints(mods...).DeleteAllG(ctx); err != nil {
+		return fmt.Errorf("deleting datapoints: %v", err)
+	}
+	return nil
+}
+
+func SaveSubscription(ctx context.Context, configID int64, subscription appmodel.Subscription) error {
+	dbSubscription := dbgen.Subscription{
+		ConfigurationID: configID,
+		Kind:            string(subscription.Kind),
+		SubscriptionID:  subscription.SubscriptionID,
+		WebhookURL:      subscription.WebhookURL,
+		LeaseTime:       int32(subscription.LeaseTime / time.Second),
+		MaxSendTime:     int32(subscription.MaxSendTime / time.Second),
+		SubscribedAt:    subscription.SubscribedAt,
+		RenewedAt:       subscription.RenewedAt,
+	}
+	conflictColumns := []string{dbgen.SubscriptionColumns.ConfigurationID, dbgen.SubscriptionColumns.Kind}
+	// The time of the last event is kept, it is maintained by TouchSubscription.
+	updateColumns := boil.Whitelist(
+		dbgen.SubscriptionColumns.SubscriptionID,
+		dbgen.SubscriptionColumns.WebhookURL,
+		dbgen.SubscriptionColumns.LeaseTime,
+		dbgen.SubscriptionColumns.MaxSendTime,
+		dbgen.SubscriptionColumns.SubscribedAt,
+		dbgen.SubscriptionColumns.RenewedAt,
+	)
+	if err := dbSubscription.UpsertG(ctx, true, conflictColumns, updateColumns, boil.Infer()); err != nil {
+		return fmt.Errorf("upserting subscription: %v", err)
+	}
+	return nil
+}
+
+func GetSubscriptions(ctx context.Context, configID int64) ([]appmodel.Subscription, error) {
+	dbSubscriptions, err := dbgen.Subscriptions(
+		dbgen.SubscriptionWhere.ConfigurationID.EQ(configID),
+	).AllG(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching subscriptions: %v", err)
+	}
+	var subscriptions []appmodel.Subscription
+	for _, dbSubscription := range dbSubscriptions {
+		subscriptions = append(subscriptions, appmodel.Subscription{
+			Kind:           appmodel.SubscriptionKind(dbSubscription.Kind),
+			SubscriptionID: dbSubscription.SubscriptionID,
+			WebhookURL:     dbSubscription.WebhookURL,
+			LeaseTime:      time.Duration(dbSubscription.LeaseTime) * time.Second,
+			MaxSendTime:    time.Duration(dbSubscription.MaxSendTime) * time.Second,
+			SubscribedAt:   dbSubscription.SubscribedAt,
+			RenewedAt:      dbSubscription.RenewedAt,
+			LastEventAt:    dbSubscription.LastEventAt.Time,
+		})
+	}
+	return subscriptions, nil
+}
+
+// TouchSubscription records that the edge sent an event for the subscription.
+func TouchSubscription(ctx context.Context, configID int64, kind appmodel.SubscriptionKind, at time.Time) error {
+	if _, err := dbgen.Subscriptions(
+		dbgen.SubscriptionWhere.ConfigurationID.EQ(configID),
+		dbgen.SubscriptionWhere.Kind.EQ(string(kind)),
+	).UpdateAllG(ctx, dbgen.M{dbgen.SubscriptionColumns.LastEventAt: at}); err != nil {
+		return fmt.Errorf("updating last event of subscription: %v", err)
+	}
+	return nil
+}
+
+func DeleteSubscriptions(ctx context.Context, configID int64) error {
+	if _, err := dbgen.Subscriptions(
+		dbgen.SubscriptionWhere.ConfigurationID.EQ(configID),
+	).DeleteAllG(ctx); err != nil {
+		return fmt.Errorf("deleting subscriptions: %v", err)
+	}
+	return nil
+}
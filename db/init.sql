@@ -24,8 +24,23 @@ create table if not exists open_bos.configuration
 	client_secret        text not null,
 	ontology_version     integer not null,
 	app_public_api_url   text not null,
+	webhook_secret       text not null default md5(gen_random_uuid()::text),
+	base_url             text not null default 'https://api.buildings.ability.abb/buildings/openbos/apiproxy/v1',
+	token_url            text not null default 'https://login.microsoftonline.com/372ee9e0-9ce0-4033-a64a-c07073a91ecd/oauth2/v2.0/token',
+	scope                text not null default 'api://openbos/.default',
 	refresh_interval     integer not null default 60,
 	request_timeout      integer not null default 120,
+	max_retries          integer not null default 3,
+	retry_base_delay     integer not null default 500,
+	retry_max_delay      integer not null default 30000,
+	deletion_policy      text not null default 'archive',
+	bad_quality_policy   text not null default 'hold',
+	clamp_future_timestamps boolean not null default false,
+	unit_preferences     json not null default '{}', -- OpenBOS unit ID to the unit ID the edge should send instead
+	unit_conversions     json not null default '[]', -- Conversions of OpenBOS units done by the app
+	tag_prefix           text not null default '', -- Prepended to OpenBOS tags in Eliona
+	tag_allow_list       json not null default '[]', -- Patterns of OpenBOS tags to propagate, empty for all
+	icon_mapping         json not null default '{}', -- OpenBOS icon to Eliona icon, overriding the built-in mapping
 	asset_filter         json not null,
 	active               boolean not null default false,
 	enable               boolean not null default false,
@@ -40,7 +55,8 @@ create table if not exists open_bos.asset
 	project_id       text      not null,
 	global_asset_id  text      not null,
 	provider_id      text      not null,
-	asset_id         integer
+	asset_id         integer,
+	stale            boolean   not null default false
 );
 
 create table if not exists open_bos.openbos_datapoint
@@ -49,14 +65,18 @@ create table if not exists open_bos.openbos_datapoint
 	asset_id    bigserial not null references open_bos.asset(id) ON DELETE CASCADE,
 	subtype     text      not null,
 	provider_id text      not null unique,
-	name        text      not null
+	name        text      not null,
+	tags        text[]    not null default '{}' -- OpenBOS tags of the datapoint template
 );
 
 create table if not exists open_bos.eliona_attribute
 (
 	id                    bigserial primary key,
 	openbos_datapoint_id  bigserial not null references open_bos.openbos_datapoint(id) ON DELETE CASCADE,
-	eliona_attribute_name text      not null
+	eliona_attribute_name text      not null,
+	unit_id               text      not null default '', -- OpenBOS unit the edge sends the values in
+	unit_symbol           text      not null default '',
+	format                text      not null default '' -- Format of the values, e.g. boolean or float
 );
 
 CREATE TABLE IF NOT EXISTS open_bos.alarm (
@@ -66,6 +86,62 @@ CREATE TABLE IF NOT EXISTS open_bos.alarm (
 	openbos_alarm_id      TEXT NOT NULL -- Not unique, because of complex mapping to multiple attributes.
 );
 
+-- Ontology as of the last synchronization, used to apply only the changes of
+-- a newer ontology version.
+create table if not exists open_bos.ontology_snapshot
+(
+	configuration_id bigint      primary key references open_bos.configuration(id) ON DELETE CASCADE,
+	version          integer     not null,
+	ontology         jsonb       not null,
+	updated_at       timestamptz not null default now()
+);
+
+-- Webhook subscriptions on the edge, needed to keep them alive and to remove them again.
+create table if not exists open_bos.subscription
+(
+	id               bigserial   primary key,
+	configuration_id bigint      not null references open_bos.configuration(id) ON DELETE CASCADE,
+	kind             text        not null,
+	subscription_id  text        not null,
+	webhook_url      text        not null,
+	lease_time       integer     not null default 0, -- seconds, 0 if the subscription never expires
+	max_send_time    integer     not null default 0, -- seconds between heartbeats, 0 if there are none
+	subscribed_at    timestamptz not null default now(),
+	renewed_at       timestamptz not null default now(),
+	last_event_at    timestamptz,
+	unique (configuration_id, kind)
+);
+
+-- Installations created by an earlier version already have the tables above,
+-- so columns added since are added here as well.
+alter table open_bos.configuration add column if not exists base_url text not null default 'https://api.buildings.ability.abb/buildings/openbos/apiproxy/v1';
+alter table open_bos.configuration add column if not exists token_url text not null default 'https://login.microsoftonline.com/372ee9e0-9ce0-4033-a64a-c07073a91ecd/oauth2/v2.0/token';
+-- Existing configurations keep the scope the app requested before it could be set.
+alter table open_bos.configuration add column if not exists scope text not null default 'api://dev.openbos/.default';
+alter table open_bos.configuration alter column scope set default 'api://openbos/.default';
+alter table open_bos.configuration add column if not exists max_retries integer not null default 3;
+alter table open_bos.configuration add column if not exists retry_base_delay integer not null default 500;
+alter table open_bos.configuration add column if not exists retry_max_delay integer not null default 30000;
+alter table open_bos.configuration add column if not exists deletion_policy text not null default 'archive';
+alter table open_bos.asset add column if not exists stale boolean not null default false;
+alter table open_bos.configuration add column if not exists webhook_secret text not null default md5(gen_random_uuid()::text);
+alter table open_bos.configuration add column if not exists bad_quality_policy text not null default 'hold';
+alter table open_bos.configuration add column if not exists clamp_future_timestamps boolean not null default false;
+alter table open_bos.configuration add column if not exists unit_preferences json not null default '{}';
+alter table open_bos.configuration add column if not exists unit_conversions json not null default '[]';
+alter table open_bos.eliona_attribute add column if not exists unit_id text not null default '';
+alter table open_bos.eliona_attribute add column if not exists unit_symbol text not null default '';
+alter table open_bos.configuration add column if not exists tag_prefix text not null default '';
+alter table open_bos.configuration add column if not exists tag_allow_list json not null default '[]';
+alter table open_bos.openbos_datapoint add column if not exists tags text[] not null default '{}';
+alter table open_bos.configuration add column if not exists icon_mapping json not null default '{}';
+alter table open_bos.eliona_attribute add column if not exists format text not null default '';
+alter table open_bos.subscription add column if not exists lease_time integer not null default 0;
+alter table open_bos.subscription add column if not exists max_send_time integer not null default 0;
+alter table open_bos.subscription add column if not exists subscribed_at timestamptz not null default now();
+alter table open_bos.subscription add column if not exists renewed_at timestamptz not null default now();
+alter table open_bos.subscription add column if not exists last_event_at timestamptz;
+
 -- There is a transaction started in app.Init(). We need to commit to make the
 -- new objects available for all other init steps.
 -- Chain starts the same transaction again.
@@ -26,7 +26,10 @@ import (
 
 var CHECK_TYPE_EXTERNAL = "external"
 
-func CreateAlarm(assetID int32, subtype, attribute string, needsAck bool, priority int, message map[string]any) (int32, error) {
+func CreateAlarm(assetID int32, subtype, attribute string, needsAck bool, priority int, message map[string]any, tags []string) (int32, error) {
+	if tags == nil {
+		tags = []string{}
+	}
 	alarmRule, _, err := client.NewClient().AlarmRulesAPI.
 		PostAlarmRule(client.AuthenticationContext()).
 		AlarmRule(api.AlarmRule{
@@ -36,7 +39,7 @@ func CreateAlarm(assetID int32, subtype, attribute string, needsAck bool, priori
 			Priority:            api.AlarmPriority(priority),
 			RequiresAcknowledge: &needsAck,
 			Message:             message,
-			Tags:                []string{}, // todo
+			Tags:                tags,
 			Enable:              api.PtrBool(true),
 			CheckType:           *api.NewNullableString(&CHECK_TYPE_EXTERNAL),
 		}).
@@ -16,67 +16,420 @@
 package eliona
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	appmodel "open-bos/app/model"
+	conf "open-bos/db/helper"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
 	"github.com/eliona-smart-building-assistant/go-eliona/asset"
 	"github.com/eliona-smart-building-assistant/go-eliona/client"
+	"github.com/eliona-smart-building-assistant/go-utils/common"
 	"github.com/eliona-smart-building-assistant/go-utils/log"
 )
 
-func CreateAssets(config appmodel.Configuration, root Asset) error {
+// ApplyOntologyDiff brings Eliona and the mappings in line with the ontology,
+// touching only the assets, datapoints and properties that the diff reports as
+// added, changed or moved. As a full diff reports everything as added, it
+// synchronizes all assets, including mappings of already existing ones.
+func ApplyOntologyDiff(config appmodel.Configuration, root Asset, diff appmodel.OntologyDiff) error {
+	changes := pendingChanges{
+		newAssets:     toSet(diff.Assets.Added, diff.Spaces.Added),
+		updatedAssets: toSet(diff.Assets.Changed, diff.Assets.Moved, diff.Spaces.Changed, diff.Spaces.Moved),
+		updatedDatapoints: toSet(diff.Datapoints.Added, diff.Datapoints.Changed, diff.Datapoints.Moved,
+			diff.Properties.Added, diff.Properties.Changed, diff.Properties.Moved),
+		updatedProperties: toSet(diff.Properties.Added, diff.Properties.Changed, diff.Properties.Moved),
+		changedTemplates:  toSet(diff.Templates.Changed),
+	}
+
 	for _, projectId := range config.ProjectIDs {
-		assetsCreated, err := asset.CreateAssets(asset.Root(&root), projectId)
-		if err != nil {
-			return err
+		if len(changes.newAssets) > 0 {
+			assetsCreated, err := asset.CreateAssets(asset.Root(&root), projectId)
+			if err != nil {
+				return err
+			}
+			if assetsCreated != 0 {
+				if err := notifyUser(config.UserId, projectId, assetsCreated); err != nil {
+					return fmt.Errorf("notifying user about CAC: %v", err)
+				}
+			}
 		}
-		if assetsCreated != 0 {
-			if err := notifyUser(config.UserId, projectId, assetsCreated); err != nil {
-				return fmt.Errorf("notifying user about CAC: %v", err)
+		if err := changes.syncRecursively(config, root, projectId, "", ""); err != nil {
+			return fmt.Errorf("synchronizing changes: %v", err)
+		}
+	}
+	return nil
+}
+
+// initializedAssetTypes holds the asset types each configuration last
+// initialized, so that unchanged ones are not sent to Eliona again, e.g. on a
+// full synchronization.
+var initializedAssetTypes = struct {
+	sync.Mutex
+	fingerprints map[int64]map[string]string // By configuration and asset type name.
+}{fingerprints: make(map[int64]map[string]string)}
+
+// InitAssetType creates or updates the asset type in Eliona, unless it is the
+// same as when the configuration last initialized it.
+func InitAssetType(configID int64, assetType api.AssetType) error {
+	fingerprint, err := assetTypeFingerprint(assetType)
+	if err != nil {
+		return fmt.Errorf("fingerprinting asset type %v: %v", assetType.Name, err)
+	}
+	initializedAssetTypes.Lock()
+	defer initializedAssetTypes.Unlock()
+	if initializedAssetTypes.fingerprints[configID][assetType.Name] == fingerprint {
+		log.Debug("eliona", "asset type %v did not change", assetType.Name)
+		return nil
+	}
+	if err := asset.InitAssetType(assetType)(nil); err != nil {
+		return err
+	}
+	if initializedAssetTypes.fingerprints[configID] == nil {
+		initializedAssetTypes.fingerprints[configID] = make(map[string]string)
+	}
+	initializedAssetTypes.fingerprints[configID][assetType.Name] = fingerprint
+	return nil
+}
+
+// ForgetAssetTypes makes the next synchronization of the configuration send
+// all its asset types to Eliona again, in case they were changed there.
+func ForgetAssetTypes(configID int64) {
+	initializedAssetTypes.Lock()
+	defer initializedAssetTypes.Unlock()
+	delete(initializedAssetTypes.fingerprints, configID)
+}
+
+// assetTypeFingerprint returns a text that changes whenever the asset type
+// does. Maps are marshalled with sorted keys, so equal asset types have equal
+// fingerprints.
+func assetTypeFingerprint(assetType api.AssetType) (string, error) {
+	fingerprint, err := json.Marshal(assetType)
+	if err != nil {
+		return "", err
+	}
+	return string(fingerprint), nil
+}
+
+const (
+	archivedTag = "archived"
+	staleTag    = "stale"
+)
+
+// ReconcileRemoved handles mapped assets and datapoints that are no longer part
+// of the ontology. Datapoints are forgotten, assets are handled according to
+// the deletion policy of the configuration.
+func ReconcileRemoved(config appmodel.Configuration, knownIDs map[string]bool) error {
+	ctx := context.Background()
+
+	providerIDs, err := conf.GetDatapointProviderIDs(ctx, config.Id)
+	if err != nil {
+		return fmt.Errorf("getting datapoints: %v", err)
+	}
+	var removedDatapoints []string
+	for _, providerID := range providerIDs {
+		if !knownIDs[providerID] {
+			removedDatapoints = append(removedDatapoints, providerID)
+		}
+	}
+	if err := conf.DeleteDatapoints(ctx, config.Id, removedDatapoints); err != nil {
+		return fmt.Errorf("deleting removed datapoints: %v", err)
+	}
+
+	assets, err := conf.GetAssets(ctx, config)
+	if err != nil {
+		return fmt.Errorf("getting assets: %v", err)
+	}
+	for _, a := range assets {
+		if a.ProviderID == "" {
+			continue // Root asset holding the orphan datapoints.
+		}
+		if knownIDs[a.ProviderID] {
+			if a.Stale {
+				log.Info("eliona", "asset %v reappeared in OpenBOS, removing stale mark", a.GlobalAssetID)
+				if err := updateAssetTags(a.AssetID, "", staleTag); err != nil {
+					return fmt.Errorf("unmarking asset %v as stale: %v", a.AssetID, err)
+				}
+				if err := conf.SetAssetStale(ctx, a.ID, false); err != nil {
+					return err
+				}
 			}
+			continue
 		}
-		if err := upsertDataRecursively(root, projectId); err != nil {
-			return fmt.Errorf("upserting data: %v", err)
+		if err := removeAsset(ctx, config.DeletionPolicy, a); err != nil {
+			return fmt.Errorf("removing asset %v: %v", a.GlobalAssetID, err)
 		}
 	}
 	return nil
 }
 
-func upsertDataRecursively(node Asset, projectId string) error {
-	assetID, err := node.GetAssetID(projectId)
+func removeAsset(ctx context.Context, policy appmodel.DeletionPolicy, a appmodel.Asset) error {
+	switch policy {
+	case appmodel.DeletionPolicyDelete:
+		log.Info("eliona", "asset %v was removed from OpenBOS, deleting Eliona asset %v", a.GlobalAssetID, a.AssetID)
+		resp, err := client.NewClient().AssetsAPI.
+			DeleteAssetById(client.AuthenticationContext(), a.AssetID).
+			Execute()
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return fmt.Errorf("deleting Eliona asset %v: %v", a.AssetID, err)
+		}
+		return conf.DeleteAsset(ctx, a.ID)
+	case appmodel.DeletionPolicyStale:
+		if a.Stale {
+			return nil
+		}
+		log.Info("eliona", "asset %v was removed from OpenBOS, marking Eliona asset %v as stale", a.GlobalAssetID, a.AssetID)
+		if err := updateAssetTags(a.AssetID, staleTag, ""); err != nil {
+			return fmt.Errorf("marking asset %v as stale: %v", a.AssetID, err)
+		}
+		return conf.SetAssetStale(ctx, a.ID, true)
+	default:
+		log.Info("eliona", "asset %v was removed from OpenBOS, archiving Eliona asset %v", a.GlobalAssetID, a.AssetID)
+		if err := updateAssetTags(a.AssetID, archivedTag, ""); err != nil {
+			return fmt.Errorf("marking asset %v as archived: %v", a.AssetID, err)
+		}
+		return conf.DeleteAsset(ctx, a.ID)
+	}
+}
+
+// updateAssetTags adds and removes a tag of an Eliona asset. Empty tags are
+// ignored.
+func updateAssetTags(assetID int32, add, remove string) error {
+	a, resp, err := client.NewClient().AssetsAPI.
+		GetAssetById(client.AuthenticationContext(), assetID).
+		Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil // Nothing to tag, the asset was deleted in Eliona already.
+	}
 	if err != nil {
-		return fmt.Errorf("getting asset ID: %v", err)
+		return fmt.Errorf("getting asset: %v", err)
 	}
-	if assetID == nil {
-		return fmt.Errorf("assetID is nil for asset %v, project %v", node.GetGAI(), projectId)
+
+	tags := slices.DeleteFunc(a.Tags, func(tag string) bool { return tag == remove })
+	if add != "" && !slices.Contains(tags, add) {
+		tags = append(tags, add)
 	}
+	a.Tags = tags
 
+	if _, _, err := client.NewClient().AssetsAPI.
+		PutAssetById(client.AuthenticationContext(), assetID).
+		Asset(*a).
+		Execute(); err != nil {
+		return fmt.Errorf("updating asset: %v", err)
+	}
+	return nil
+}
+
+// pendingChanges holds the IDs of the ontology entities a synchronization
+// needs to touch.
+type pendingChanges struct {
+	newAssets         map[string]bool
+	updatedAssets     map[string]bool // Renamed, moved or with a changed template.
+	updatedDatapoints map[string]bool
+	updatedProperties map[string]bool
+	changedTemplates  map[string]bool
+}
+
+// syncRecursively walks the asset tree the same way asset.CreateAssets does, so
+// that parents are resolved to the same Eliona assets.
+func (c pendingChanges) syncRecursively(config appmodel.Configuration, node Asset, projectId, locationalParentGAI, functionalParentGAI string) error {
+	isNew := c.newAssets[node.ID]
+	switch {
+	case isNew:
+		// Assets are created without tags. Existing assets reported as new by
+		// a full diff might carry tags that have to be removed.
+		if len(node.Tags) > 0 || config.TagPrefix != "" {
+			if err := tagAsset(config, node, projectId); err != nil {
+				return fmt.Errorf("tagging asset %v: %v", node.GetGAI(), err)
+			}
+		}
+	case c.updatedAssets[node.ID]:
+		if err := updateAsset(config, node, projectId, locationalParentGAI, functionalParentGAI); err != nil {
+			return fmt.Errorf("updating asset %v: %v", node.GetGAI(), err)
+		}
+	case c.changedTemplates[node.TemplateID]:
+		// The tags of the template might have changed.
+		if err := tagAsset(config, node, projectId); err != nil {
+			return fmt.Errorf("tagging asset %v: %v", node.GetGAI(), err)
+		}
+	}
+
+	// If the template changed, the datapoints of the asset might have got
+	// different attributes, or might not be part of the template anymore.
+	templateChanged := !isNew && c.changedTemplates[node.TemplateID]
+	var datapoints []appmodel.Datapoint
 	for _, datapoint := range node.Datapoints {
-		if datapoint.Data == nil || len(datapoint.Data) == 0 {
-			continue
+		if isNew || templateChanged || c.updatedDatapoints[datapoint.ProviderID] {
+			datapoints = append(datapoints, datapoint)
+		}
+	}
+
+	if len(datapoints) > 0 || templateChanged {
+		ctx := context.Background()
+		dbAsset, err := conf.GetAsset(ctx, config, projectId, node.GetGAI())
+		if errors.Is(err, conf.ErrNotFound) && templateChanged && len(datapoints) == 0 {
+			return nil // Not mapped, nothing to align.
 		}
-		if err := UpsertAssetData(*assetID, datapoint.Data, time.Now(), api.DataSubtype(datapoint.Subtype)); err != nil {
-			return fmt.Errorf("upserting asset data %v for asset ID %v subtype %v: %v", datapoint.Data, *assetID, datapoint.Subtype, err)
+		if err != nil {
+			return fmt.Errorf("getting asset %v in project %v: %v", node.GetGAI(), projectId, err)
+		}
+		if templateChanged {
+			var providerIDs []string
+			for _, datapoint := range node.Datapoints {
+				providerIDs = append(providerIDs, datapoint.ProviderID)
+			}
+			if err := conf.DeleteAssetDatapointsExcept(ctx, dbAsset.ID, providerIDs); err != nil {
+				return fmt.Errorf("deleting datapoints no longer part of template %v: %v", node.TemplateID, err)
+			}
+		}
+		for _, datapoint := range datapoints {
+			if err := conf.SyncDatapoint(ctx, dbAsset.ID, datapoint); err != nil {
+				return fmt.Errorf("synchronizing datapoint: %v", err)
+			}
+			if len(datapoint.Data) == 0 || !(isNew || c.updatedProperties[datapoint.ProviderID]) {
+				continue
+			}
+			if err := UpsertAssetData(dbAsset.AssetID, datapoint.Data, time.Now(), api.DataSubtype(datapoint.Subtype)); err != nil {
+				return fmt.Errorf("upserting asset data %v for asset ID %v subtype %v: %v", datapoint.Data, dbAsset.AssetID, datapoint.Subtype, err)
+			}
 		}
 	}
 
 	for _, child := range node.getLocationalAssetChildren() {
-		if err := upsertDataRecursively(child, projectId); err != nil {
+		if err := c.syncRecursively(config, child, projectId, node.GetGAI(), functionalParentGAI); err != nil {
 			return err
 		}
 	}
-
 	for _, child := range node.getFunctionalAssetChildren() {
-		if err := upsertDataRecursively(child, projectId); err != nil {
+		if err := c.syncRecursively(config, child, projectId, locationalParentGAI, node.GetGAI()); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// updateAsset updates name, type, parents and tags of an existing Eliona asset
+// in place, so that its ID, data history and alarm rules are preserved.
+func updateAsset(config appmodel.Configuration, node Asset, projectId, locationalParentGAI, functionalParentGAI string) error {
+	ctx := context.Background()
+	assetID, err := conf.GetAssetId(ctx, config, projectId, node.GetGAI())
+	if err != nil {
+		return fmt.Errorf("getting asset ID: %v", err)
+	}
+	if assetID == nil {
+		return nil // Not mapped, e.g. excluded by the asset filter until now.
+	}
+	locationalParentID, err := parentAssetID(ctx, config, projectId, locationalParentGAI)
+	if err != nil {
+		return fmt.Errorf("getting locational parent: %v", err)
+	}
+	functionalParentID, err := parentAssetID(ctx, config, projectId, functionalParentGAI)
+	if err != nil {
+		return fmt.Errorf("getting functional parent: %v", err)
+	}
+
+	a, _, err := client.NewClient().AssetsAPI.
+		GetAssetById(client.AuthenticationContext(), *assetID).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("getting asset %v: %v", *assetID, err)
+	}
+	a.Name = *api.NewNullableString(common.Ptr(node.GetName()))
+	a.AssetType = node.GetAssetType()
+	a.ParentLocationalAssetId = *api.NewNullableInt32(locationalParentID)
+	a.ParentFunctionalAssetId = *api.NewNullableInt32(functionalParentID)
+	a.Tags = mergeTags(a.Tags, node.Tags, config.TagPrefix)
+
+	log.Debug("eliona", "updating asset %v: name '%v', type %v, parents %v/%v", *assetID, node.GetName(), a.AssetType, locationalParentID, functionalParentID)
+	if _, _, err := client.NewClient().AssetsAPI.
+		PutAssetById(client.AuthenticationContext(), *assetID).
+		Asset(*a).
+		Execute(); err != nil {
+		return fmt.Errorf("putting asset %v: %v", *assetID, err)
+	}
+	return nil
+}
+
+// tagAsset brings the tags of an existing Eliona asset in line with the tags
+// of the OpenBOS asset.
+func tagAsset(config appmodel.Configuration, node Asset, projectId string) error {
+	assetID, err := conf.GetAssetId(context.Background(), config, projectId, node.GetGAI())
+	if err != nil {
+		return fmt.Errorf("getting asset ID: %v", err)
+	}
+	if assetID == nil {
+		return nil // Not mapped, e.g. excluded by the asset filter.
+	}
+
+	a, resp, err := client.NewClient().AssetsAPI.
+		GetAssetById(client.AuthenticationContext(), *assetID).
+		Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil // Nothing to tag, the asset was deleted in Eliona already.
+	}
+	if err != nil {
+		return fmt.Errorf("getting asset %v: %v", *assetID, err)
+	}
+	tags := mergeTags(a.Tags, node.Tags, config.TagPrefix)
+	if slices.Equal(tags, a.Tags) {
+		return nil
+	}
+	a.Tags = tags
 
+	log.Debug("eliona", "updating tags of asset %v: %v", *assetID, tags)
+	if _, _, err := client.NewClient().AssetsAPI.
+		PutAssetById(client.AuthenticationContext(), *assetID).
+		Asset(*a).
+		Execute(); err != nil {
+		return fmt.Errorf("putting asset %v: %v", *assetID, err)
+	}
 	return nil
 }
 
+// mergeTags adds the tags mapped from OpenBOS to the current tags of an Eliona
+// asset. Tags with the tag prefix are owned by the app and removed if OpenBOS
+// no longer reports them. Without a prefix, the app cannot tell its tags from
+// the ones added in Eliona, so no tag is ever removed. The tags marking removed
+// assets are left alone either way.
+func mergeTags(current, openBOSTags []string, prefix string) []string {
+	tags := slices.DeleteFunc(slices.Clone(current), func(tag string) bool {
+		if prefix == "" || tag == archivedTag || tag == staleTag {
+			return false
+		}
+		return strings.HasPrefix(tag, prefix) && !slices.Contains(openBOSTags, tag)
+	})
+	for _, tag := range openBOSTags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func parentAssetID(ctx context.Context, config appmodel.Configuration, projectId, parentGAI string) (*int32, error) {
+	if parentGAI == "" {
+		return nil, nil
+	}
+	return conf.GetAssetId(ctx, config, projectId, parentGAI)
+}
+
+func toSet(idLists ...[]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, ids := range idLists {
+		for _, id := range ids {
+			set[id] = true
+		}
+	}
+	return set
+}
+
 func notifyUser(userId string, projectId string, assetsCreated int) error {
 	receipt, _, err := client.NewClient().CommunicationAPI.
 		PostNotification(client.AuthenticationContext()).
@@ -0,0 +1,48 @@
+package eliona
+
+import (
+	"testing"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTags(t *testing.T) {
+	current := []string{"manual", "bos:old", "bos:kept", "stale"}
+
+	assert.Equal(t, []string{"manual", "bos:kept", "stale", "bos:new"},
+		mergeTags(current, []string{"bos:kept", "bos:new"}, "bos:"))
+	assert.Equal(t, []string{"manual", "bos:old", "bos:kept", "stale", "new"},
+		mergeTags(current, []string{"bos:kept", "new"}, ""), "without a prefix, no tag is removed")
+	assert.Equal(t, []string{"manual", "bos:old", "bos:kept", "stale"}, current, "current tags must not be modified")
+}
+
+func TestAssetTypeFingerprint(t *testing.T) {
+	assetType := func(mapping []map[string]any) api.AssetType {
+		return api.AssetType{
+			Name:       "open_bos_template",
+			Attributes: []api.AssetTypeAttribute{{Name: "State", Subtype: api.SUBTYPE_INPUT, Map: mapping}},
+		}
+	}
+	mapping := []map[string]any{{"value": 0, "map": "Off"}, {"value": 1, "map": "On"}}
+
+	first, err := assetTypeFingerprint(assetType(mapping))
+	assert.NoError(t, err)
+	second, err := assetTypeFingerprint(assetType([]map[string]any{{"map": "Off", "value": 0}, {"map": "On", "value": 1}}))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second, "equal asset types should have equal fingerprints")
+
+	changed, err := assetTypeFingerprint(assetType([]map[string]any{{"value": 0, "map": "Off"}, {"value": 1, "map": "Running"}}))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, changed, "a changed mapping should change the fingerprint")
+}
+
+func TestForgetAssetTypes(t *testing.T) {
+	initializedAssetTypes.fingerprints[-1] = map[string]string{"open_bos_ahu": "fingerprint"}
+	initializedAssetTypes.fingerprints[-2] = map[string]string{"open_bos_ahu": "fingerprint"}
+	defer ForgetAssetTypes(-2)
+
+	ForgetAssetTypes(-1)
+	assert.NotContains(t, initializedAssetTypes.fingerprints, int64(-1))
+	assert.Contains(t, initializedAssetTypes.fingerprints, int64(-2), "other configurations keep their asset types")
+}
@@ -54,3 +54,83 @@ func GetAssetData(assetID int32, subtype string) (api.Data, error) {
 	}
 	return datas[0], nil
 }
+
+// AssetData holds values of one asset and subtype at one point in time.
+type AssetData struct {
+	AssetID   int32
+	Subtype   api.DataSubtype
+	Timestamp time.Time
+	Data      map[string]any
+}
+
+type assetDataKey struct {
+	assetID   int32
+	subtype   api.DataSubtype
+	timestamp int64
+}
+
+// mergeAssetData merges records of the same asset, subtype and timestamp. The
+// records keep the order they first appeared in, later values overwrite
+// earlier ones.
+func mergeAssetData(records []AssetData) []AssetData {
+	var merged []AssetData
+	index := make(map[assetDataKey]int)
+	for _, record := range records {
+		key := assetDataKey{assetID: record.AssetID, subtype: record.Subtype, timestamp: record.Timestamp.UnixNano()}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, AssetData{
+				AssetID:   record.AssetID,
+				Subtype:   record.Subtype,
+				Timestamp: record.Timestamp,
+				Data:      make(map[string]any, len(record.Data)),
+			})
+			i = len(merged) - 1
+		}
+		for name, value := range record.Data {
+			merged[i].Data[name] = value
+		}
+	}
+	return merged
+}
+
+// UpsertAssetDataBulk merges the records and sends them to Eliona in a single
+// request. Records of assets that no longer exist in Eliona are skipped, as are
+// those of assets whose existence could not be checked.
+func UpsertAssetDataBulk(records []AssetData) error {
+	cr := ClientReference
+	exists := make(map[int32]bool)
+	var datas []api.Data
+	for _, record := range mergeAssetData(records) {
+		assetExists, checked := exists[record.AssetID]
+		if !checked {
+			var err error
+			assetExists, err = asset.ExistAsset(record.AssetID)
+			if err != nil {
+				log.Error("Eliona", "checking if asset %v exists, skipping its data: %v", record.AssetID, err)
+				assetExists = false
+			}
+			exists[record.AssetID] = assetExists
+		}
+		if !assetExists {
+			continue
+		}
+		timestamp := record.Timestamp
+		datas = append(datas, api.Data{
+			AssetId:         record.AssetID,
+			Subtype:         record.Subtype,
+			Timestamp:       *api.NewNullableTime(&timestamp),
+			Data:            record.Data,
+			ClientReference: *api.NewNullableString(&cr),
+		})
+	}
+	if len(datas) == 0 {
+		return nil
+	}
+	log.Debug("Eliona", "upserting %v data records of %v assets", len(datas), len(exists))
+	if err := asset.UpsertDataBulk(datas); err != nil {
+		return fmt.Errorf("upserting data bulk: %v", err)
+	}
+	return nil
+}
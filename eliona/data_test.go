@@ -0,0 +1,30 @@
+package eliona
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/eliona-smart-building-assistant/go-eliona-api-client/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeAssetData(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Second)
+
+	merged := mergeAssetData([]AssetData{
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: now, Data: map[string]any{"a": 1}},
+		{AssetID: 2, Subtype: api.SUBTYPE_INPUT, Timestamp: now, Data: map[string]any{"a": 2}},
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: now.In(time.FixedZone("CET", 3600)), Data: map[string]any{"b": 1}},
+		{AssetID: 1, Subtype: api.SUBTYPE_OUTPUT, Timestamp: now, Data: map[string]any{"c": 1}},
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: later, Data: map[string]any{"a": 3}},
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: now, Data: map[string]any{"a": 4}},
+	})
+
+	assert.Equal(t, []AssetData{
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: now, Data: map[string]any{"a": 4, "b": 1}},
+		{AssetID: 2, Subtype: api.SUBTYPE_INPUT, Timestamp: now, Data: map[string]any{"a": 2}},
+		{AssetID: 1, Subtype: api.SUBTYPE_OUTPUT, Timestamp: now, Data: map[string]any{"c": 1}},
+		{AssetID: 1, Subtype: api.SUBTYPE_INPUT, Timestamp: later, Data: map[string]any{"a": 3}},
+	}, merged)
+}
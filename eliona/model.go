@@ -34,6 +34,8 @@ type Asset struct {
 
 	IsMaster int8 `eliona:"is_master" subtype:"property"`
 
+	Tags []string // Eliona tags mapped from the OpenBOS tags of the asset and its template.
+
 	LocationalChildrenMap   map[string]Asset
 	FunctionalChildrenSlice []Asset
 
@@ -16,8 +16,11 @@
 package main
 
 import (
+	"context"
 	"open-bos/app"
 	"open-bos/webhook"
+	"os/signal"
+	"syscall"
 	"time"
 
 	elionaapp "github.com/eliona-smart-building-assistant/go-eliona/app"
@@ -49,9 +52,17 @@ func main() {
 	// Initialize the app
 	app.Initialize()
 
+	// Abort OpenBOS calls in flight once the app is asked to terminate.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGINT)
+	defer stop()
+	app.SetBaseContext(ctx)
+
 	// Starting the service to collect the data for this app.
 	common.WaitForWithOs(
 		common.Loop(app.CollectData, time.Second),
+		common.Loop(app.MaintainSubscriptions, 30*time.Second),
+		common.Loop(app.LogCacheStatistics, time.Minute),
+		common.Loop(app.LogUnknownValues, time.Minute),
 		app.ListenApi,
 		app.ListenForOutputChanges,
 		app.ListenForAlarmChanges,
@@ -72,6 +72,7 @@ WHERE name LIKE 'OpenBOS%';
 -- Dev reset (without configuration and installation):
 SET SCHEMA 'public';
 DELETE FROM open_bos.asset;
+DELETE FROM open_bos.ontology_snapshot;
 
 DELETE FROM public.heap
 WHERE asset_id IN (
@@ -0,0 +1,307 @@
+//  This file is part of the Eliona project.
+//  Copyright © 2024 IoTEC AG. All Rights Reserved.
+//  ______ _ _
+// |  ____| (_)
+// | |__  | |_  ___  _ __   __ _
+// |  __| | | |/ _ \| '_ \ / _` |
+// | |____| | | (_) | | | | (_| |
+// |______|_|_|\___/|_| |_|\__,_|
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+//  BUT NOT LIMITED  TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+//  NON INFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+//  DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package webhook
+
+import (
+	"fmt"
+	"hash/fnv"
+	apiservices "open-bos/api/services"
+	"open-bos/app"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eliona-smart-building-assistant/go-utils/log"
+)
+
+const (
+	workersPerConfig   = 4
+	queueSizePerWorker = 256
+	// maxBatchSize limits how many queued updates a worker writes to Eliona at once.
+	maxBatchSize = 100
+)
+
+// ingestQueue decouples receiving live data from writing it to Eliona, so that
+// the webhook can answer before the edge gives up waiting. Every configuration
+// gets its own workers. Updates of the same asset go to the same worker, which
+// lets the worker merge the values of an asset into one data record. While a
+// datapoint has updates queued, its further updates go to the same worker even
+// if its key changed meanwhile, which keeps the updates of each datapoint in
+// order.
+type ingestQueue struct {
+	mu      sync.Mutex
+	configs map[int64]*configQueue
+	// process writes a batch of updates to Eliona and returns how many of
+	// them failed.
+	process func([]app.AttributeDataUpdate) (failed int)
+	// shardKey returns the key that decides which worker processes the
+	// updates of a datapoint. Called while receiving, so it must not block.
+	shardKey func(configID int64, datapointID string) string
+}
+
+type configQueue struct {
+	enqueueMu sync.Mutex // Serializes producers, so that free capacity can only grow while enqueueing.
+	shards    []chan app.AttributeDataUpdate
+	closed    bool // Set once the configuration was removed. Guarded by enqueueMu.
+	// pinned holds the worker of each datapoint that has updates queued or in
+	// process. Guarded by enqueueMu.
+	pinned map[string]*pin
+
+	processed atomic.Int64
+	failed    atomic.Int64
+	rejected  atomic.Int64
+	maxDepth  atomic.Int64
+}
+
+// queueStats describes the state of the queue of one configuration.
+type pin struct {
+	shard   int
+	pending int
+}
+
+type queueStats struct {
+	Depth     int   // Updates waiting to be processed.
+	MaxDepth  int64 // Highest depth seen so far.
+	Processed int64 // Updates written to Eliona.
+	Failed    int64 // Updates that could not be written to Eliona.
+	Rejected  int64 // Updates not accepted because the queue was full.
+}
+
+func (s queueStats) String() string {
+	return fmt.Sprintf("depth %d (max %d), processed %d, failed %d, rejected %d", s.Depth, s.MaxDepth, s.Processed, s.Failed, s.Rejected)
+}
+
+func newIngestQueue(process func([]app.AttributeDataUpdate) int, shardKey func(configID int64, datapointID string) string) *ingestQueue {
+	return &ingestQueue{
+		configs:  make(map[int64]*configQueue),
+		process:  process,
+		shardKey: shardKey,
+	}
+}
+
+func (q *ingestQueue) forConfig(configID int64) *configQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if cq, ok := q.configs[configID]; ok {
+		return cq
+	}
+	cq := &configQueue{pinned: make(map[string]*pin)}
+	for i := 0; i < workersPerConfig; i++ {
+		shard := make(chan app.AttributeDataUpdate, queueSizePerWorker)
+		cq.shards = append(cq.shards, shard)
+		go func() {
+			for update := range shard {
+				batch := takeBatch(update, shard)
+				failed := q.process(batch)
+				cq.processed.Add(int64(len(batch) - failed))
+				cq.failed.Add(int64(failed))
+				cq.unpin(batch)
+			}
+		}()
+	}
+	q.configs[configID] = cq
+	return cq
+}
+
+// enqueue accepts either all updates of a batch or none of them. It never
+// blocks; if the queue is full, the edge is expected to deliver the batch again.
+func (q *ingestQueue) enqueue(configID int64, updates []app.AttributeDataUpdate) bool {
+	cq := q.forConfig(configID)
+	shards := make([]int, len(updates))
+	for i, update := range updates {
+		shards[i] = cq.shardOf(q.shardKey(configID, update.DatapointProviderID))
+	}
+	cq.enqueueMu.Lock()
+	defer cq.enqueueMu.Unlock()
+
+	if cq.closed {
+		// The configuration was removed meanwhile.
+		return false
+	}
+	for i, update := range updates {
+		if p, ok := cq.pinned[update.DatapointProviderID]; ok {
+			shards[i] = p.shard
+		} else {
+			// Later updates of the same batch follow the first one.
+			cq.pinned[update.DatapointProviderID] = &pin{shard: shards[i]}
+		}
+	}
+	needed := make([]int, len(cq.shards))
+	for _, shard := range shards {
+		needed[shard]++
+	}
+	for i, shard := range cq.shards {
+		if cap(shard)-len(shard) < needed[i] {
+			cq.rejected.Add(int64(len(updates)))
+			cq.dropUnusedPins()
+			return false
+		}
+	}
+	for i, update := range updates {
+		cq.pinned[update.DatapointProviderID].pending++
+		cq.shards[shards[i]] <- update
+	}
+
+	depth := int64(cq.depth())
+	for {
+		max := cq.maxDepth.Load()
+		if depth <= max || cq.maxDepth.CompareAndSwap(max, depth) {
+			break
+		}
+	}
+	return true
+}
+
+// remove stops the workers of a configuration once they processed the updates
+// already queued. Data received for the configuration later on starts new ones.
+func (q *ingestQueue) remove(configID int64) {
+	q.mu.Lock()
+	cq, ok := q.configs[configID]
+	delete(q.configs, configID)
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	cq.enqueueMu.Lock()
+	defer cq.enqueueMu.Unlock()
+	cq.closed = true
+	for _, shard := range cq.shards {
+		close(shard)
+	}
+}
+
+// takeBatch adds whatever else is already waiting in the queue to the update,
+// so that it can be written to Eliona together.
+func takeBatch(first app.AttributeDataUpdate, shard <-chan app.AttributeDataUpdate) []app.AttributeDataUpdate {
+	batch := []app.AttributeDataUpdate{first}
+	for len(batch) < maxBatchSize {
+		select {
+		case update, ok := <-shard:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, update)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// unpin releases the datapoints of a processed batch from their worker once
+// none of their updates are left.
+func (cq *configQueue) unpin(batch []app.AttributeDataUpdate) {
+	cq.enqueueMu.Lock()
+	defer cq.enqueueMu.Unlock()
+	for _, update := range batch {
+		p := cq.pinned[update.DatapointProviderID]
+		if p.pending--; p.pending == 0 {
+			delete(cq.pinned, update.DatapointProviderID)
+		}
+	}
+}
+
+// dropUnusedPins removes the pins of a rejected batch that have no updates
+// queued. Must be called with enqueueMu held.
+func (cq *configQueue) dropUnusedPins() {
+	for datapointID, p := range cq.pinned {
+		if p.pending == 0 {
+			delete(cq.pinned, datapointID)
+		}
+	}
+}
+
+func (cq *configQueue) shardOf(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(cq.shards)))
+}
+
+func (cq *configQueue) depth() (depth int) {
+	for _, shard := range cq.shards {
+		depth += len(shard)
+	}
+	return depth
+}
+
+func (q *ingestQueue) stats() map[int64]queueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := make(map[int64]queueStats, len(q.configs))
+	for configID, cq := range q.configs {
+		stats[configID] = cq.stats()
+	}
+	return stats
+}
+
+func (cq *configQueue) stats() queueStats {
+	return queueStats{
+		Depth:     cq.depth(),
+		MaxDepth:  cq.maxDepth.Load(),
+		Processed: cq.processed.Load(),
+		Failed:    cq.failed.Load(),
+		Rejected:  cq.rejected.Load(),
+	}
+}
+
+// configStats returns the state of the queue of a configuration, or false if
+// it did not receive data since it was started.
+func (q *ingestQueue) configStats(configID int64) (apiservices.LiveDataQueueStats, bool) {
+	q.mu.Lock()
+	cq, ok := q.configs[configID]
+	q.mu.Unlock()
+	if !ok {
+		return apiservices.LiveDataQueueStats{}, false
+	}
+	s := cq.stats()
+	return apiservices.LiveDataQueueStats{
+		Depth:     int64(s.Depth),
+		MaxDepth:  s.MaxDepth,
+		Processed: s.Processed,
+		Failed:    s.Failed,
+		Rejected:  s.Rejected,
+	}, true
+}
+
+// logStats periodically logs the queue state of configurations that received
+// data since the last report.
+func (q *ingestQueue) logStats(interval time.Duration) {
+	reported := make(map[int64]queueStats)
+	for range time.Tick(interval) {
+		stats := q.stats()
+		configIDs := make([]int64, 0, len(stats))
+		for configID := range stats {
+			configIDs = append(configIDs, configID)
+		}
+		sort.Slice(configIDs, func(i, j int) bool { return configIDs[i] < configIDs[j] })
+		for _, configID := range configIDs {
+			s := stats[configID]
+			if s == reported[configID] {
+				continue
+			}
+			reported[configID] = s
+			if s.Rejected > 0 || s.Failed > 0 {
+				log.Warn("webhook", "Live data queue of config %d: %v", configID, s)
+			} else {
+				log.Info("webhook", "Live data queue of config %d: %v", configID, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"fmt"
+	"open-bos/app"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func datapointKey(configID int64, datapointID string) string {
+	return datapointID
+}
+
+func TestIngestQueueKeepsOrderPerDatapoint(t *testing.T) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	received := make(map[string][]int)
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, update := range batch {
+			received[update.DatapointProviderID] = append(received[update.DatapointProviderID], update.Value.(int))
+			wg.Done()
+		}
+		return 0
+	}, datapointKey)
+
+	var updates []app.AttributeDataUpdate
+	for i := 0; i < 100; i++ {
+		updates = append(updates, app.AttributeDataUpdate{ConfigID: 1, DatapointProviderID: fmt.Sprintf("dp-%d", i%10), Value: i})
+	}
+	wg.Add(len(updates))
+	assert.True(t, q.enqueue(1, updates))
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		values := received[fmt.Sprintf("dp-%d", i)]
+		assert.Len(t, values, 10)
+		assert.IsIncreasing(t, values)
+	}
+	assert.Equal(t, int64(100), q.stats()[1].Processed)
+}
+
+func TestIngestQueueProcessesInBatches(t *testing.T) {
+	release := make(chan struct{})
+	batches := make(chan int, 10)
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		<-release
+		batches <- len(batch)
+		return 0
+	}, datapointKey)
+
+	update := []app.AttributeDataUpdate{{ConfigID: 1, DatapointProviderID: "dp"}}
+	assert.True(t, q.enqueue(1, update))
+	// The worker is busy with the first update while the others arrive.
+	assert.Eventually(t, func() bool { return q.stats()[1].Depth == 0 }, time.Second, time.Millisecond)
+	for i := 0; i < 5; i++ {
+		assert.True(t, q.enqueue(1, update))
+	}
+	close(release)
+
+	assert.Equal(t, 1, <-batches)
+	assert.Equal(t, 5, <-batches)
+}
+
+func TestIngestQueueRejectsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		<-release
+		return 0
+	}, datapointKey)
+	defer close(release)
+
+	// All updates of one datapoint end up in the same worker queue. Some of them
+	// are taken by the blocked worker.
+	batch := func(n int) (updates []app.AttributeDataUpdate) {
+		for i := 0; i < n; i++ {
+			updates = append(updates, app.AttributeDataUpdate{ConfigID: 1, DatapointProviderID: "dp"})
+		}
+		return updates
+	}
+	assert.True(t, q.enqueue(1, batch(queueSizePerWorker)))
+	assert.Eventually(t, func() bool { return q.stats()[1].Depth < queueSizePerWorker }, time.Second, time.Millisecond)
+	free := queueSizePerWorker - q.stats()[1].Depth
+	assert.True(t, q.enqueue(1, batch(free)))
+	assert.False(t, q.enqueue(1, batch(1)), "queue should be full")
+
+	stats := q.stats()[1]
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(queueSizePerWorker), stats.MaxDepth)
+
+	// Other configurations are not affected.
+	assert.True(t, q.enqueue(2, batch(1)))
+}
+
+func TestIngestQueueProcessesAssetsTogether(t *testing.T) {
+	release := make(chan struct{})
+	batches := make(chan []app.AttributeDataUpdate, 10)
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		<-release
+		batches <- batch
+		return 0
+	}, func(configID int64, datapointID string) string {
+		asset, _, _ := strings.Cut(datapointID, "/")
+		return asset
+	})
+
+	// Block the worker of the asset, so that the following updates queue up.
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{{ConfigID: 1, DatapointProviderID: "ahu/blocker"}}))
+	assert.Eventually(t, func() bool { return q.stats()[1].Depth == 0 }, time.Second, time.Millisecond)
+	var updates []app.AttributeDataUpdate
+	for i := 0; i < 8; i++ {
+		updates = append(updates, app.AttributeDataUpdate{ConfigID: 1, DatapointProviderID: fmt.Sprintf("ahu/dp-%d", i)})
+	}
+	assert.True(t, q.enqueue(1, updates))
+	close(release)
+
+	assert.Len(t, <-batches, 1)
+	assert.Equal(t, updates, <-batches, "all datapoints of the asset are processed in one batch")
+}
+
+func TestIngestQueueRemove(t *testing.T) {
+	var mu sync.Mutex
+	var processed []string
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, update := range batch {
+			processed = append(processed, update.DatapointProviderID)
+		}
+		return 0
+	}, datapointKey)
+
+	cq := q.forConfig(1)
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{{ConfigID: 1, DatapointProviderID: "before"}}))
+	q.remove(1)
+	assert.NotContains(t, q.stats(), int64(1))
+	assert.True(t, cq.closed)
+
+	// Updates already queued are still processed, later ones get new workers.
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{{ConfigID: 1, DatapointProviderID: "after"}}))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(processed) == 2
+	}, time.Second, time.Millisecond)
+	assert.NotSame(t, cq, q.forConfig(1))
+
+	// Removing an unknown configuration does nothing.
+	q.remove(2)
+}
+
+func TestIngestQueueCountsFailedUpdates(t *testing.T) {
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) (failed int) {
+		for _, update := range batch {
+			if update.Value == "bad" {
+				failed++
+			}
+		}
+		return failed
+	}, datapointKey)
+
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{
+		{ConfigID: 1, DatapointProviderID: "dp", Value: "good"},
+		{ConfigID: 1, DatapointProviderID: "dp", Value: "bad"},
+		{ConfigID: 1, DatapointProviderID: "dp", Value: "good"},
+	}))
+	assert.Eventually(t, func() bool {
+		stats := q.stats()[1]
+		return stats.Processed == 2 && stats.Failed == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestIngestQueueConfigStats(t *testing.T) {
+	release := make(chan struct{})
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		<-release
+		return 0
+	}, datapointKey)
+	defer close(release)
+
+	_, ok := q.configStats(1)
+	assert.False(t, ok, "no queue before data was received")
+
+	assert.False(t, q.enqueue(1, make([]app.AttributeDataUpdate, queueSizePerWorker+1)))
+	stats, ok := q.configStats(1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(queueSizePerWorker+1), stats.Rejected)
+}
+
+func TestIngestQueueKeepsDatapointOnWorkerWhileQueued(t *testing.T) {
+	release := make(chan struct{})
+	processed := make(chan []app.AttributeDataUpdate, 10)
+	var mu sync.Mutex
+	key := "before"
+	q := newIngestQueue(func(batch []app.AttributeDataUpdate) int {
+		<-release
+		processed <- batch
+		return 0
+	}, func(configID int64, datapointID string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		return key
+	})
+
+	first := app.AttributeDataUpdate{ConfigID: 1, DatapointProviderID: "dp", Value: 1}
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{first}))
+	cq := q.forConfig(1)
+	// The key changes, e.g. because the datapoint got cached meanwhile.
+	mu.Lock()
+	for i := 0; cq.shardOf(key) == cq.shardOf("before"); i++ {
+		key = fmt.Sprintf("after-%d", i)
+	}
+	mu.Unlock()
+	second := app.AttributeDataUpdate{ConfigID: 1, DatapointProviderID: "dp", Value: 2}
+	assert.True(t, q.enqueue(1, []app.AttributeDataUpdate{second}))
+	close(release)
+
+	var values []any
+	for len(values) < 2 {
+		for _, update := range <-processed {
+			values = append(values, update.Value)
+		}
+	}
+	assert.Equal(t, []any{1, 2}, values)
+	assert.Eventually(t, func() bool {
+		cq.enqueueMu.Lock()
+		defer cq.enqueueMu.Unlock()
+		return len(cq.pinned) == 0
+	}, time.Second, time.Millisecond, "the datapoint is released once processed")
+}
@@ -16,15 +16,23 @@
 package webhook
 
 import (
+	apiservices "open-bos/api/services"
 	"open-bos/app"
+	appmodel "open-bos/app/model"
+	"open-bos/broker"
+	dbhelper "open-bos/db/helper"
 
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/eliona-smart-building-assistant/go-utils/log"
@@ -32,20 +40,32 @@ import (
 
 type webhookServer struct {
 	mux *http.ServeMux
+
+	// webhookSecret returns the secret the webhook URLs of a configuration
+	// have to contain. Replaceable for tests.
+	webhookSecret func(ctx context.Context, configID int64) (string, error)
+
+	rejectedMu sync.Mutex
+	rejected   map[int64]int // Number of rejected requests per configuration.
+
+	ingest *ingestQueue
 }
 
 func newWebhookServer() *webhookServer {
 	return &webhookServer{
-		mux: http.NewServeMux(),
+		mux:           http.NewServeMux(),
+		webhookSecret: dbhelper.GetWebhookSecret,
+		rejected:      make(map[int64]int),
+		ingest:        newIngestQueue(app.UpdateDataPointsInEliona, app.DatapointAssetKey),
 	}
 }
 
 func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	log.Debug("webhook", "Received request for URL: %s, Method: %s", r.URL.Path, r.Method)
+	log.Debug("webhook", "Received request for URL: %s, Method: %s", redactSecret(r.URL.Path), r.Method)
 
 	configID, err := parseConfigIDFromPath(r.URL.Path)
 	if err != nil {
-		log.Warn("webhook", "Invalid URL path, missing or invalid config ID: %s", r.URL.Path)
+		log.Warn("webhook", "Invalid URL path, missing or invalid config ID: %s", redactSecret(r.URL.Path))
 		http.Error(w, "Invalid URL path, missing or invalid config ID", http.StatusBadRequest)
 		return
 	}
@@ -56,6 +76,20 @@ func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	r.URL.Path = removeConfigIDFromPath(r.URL.Path)
 
+	secret, path := splitSecretFromPath(r.URL.Path)
+	if status := s.verify(ctx, configID, secret); status != http.StatusOK {
+		if status == http.StatusUnauthorized {
+			// Only configurations that exist are counted, callers choose the ID.
+			count := s.reject(configID)
+			log.Warn("webhook", "Rejected request for config %d to %s (%d rejected so far)", configID, path, count)
+		} else {
+			log.Warn("webhook", "Rejected request for config %d to %s with status %d", configID, path, status)
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	r.URL.Path = path
+
 	// Use a custom ResponseWriter to capture all status codes
 	lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 	s.mux.ServeHTTP(lrw, r)
@@ -100,6 +134,7 @@ func (s *webhookServer) handleOntologyVersion(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	app.RecordSubscriptionEvent(configID, appmodel.SubscriptionKindOntology)
 
 	if ontologyResponse.NotificationIdentifier == "StructureVersion" {
 		log.Info("webhook", "collecting structure version update for ConfigID=%d: Version=%d", configID, ontologyResponse.Version)
@@ -113,6 +148,7 @@ func (s *webhookServer) handleOntologyVersion(w http.ResponseWriter, r *http.Req
 
 func (s *webhookServer) handleLivedataUpdate(w http.ResponseWriter, r *http.Request) {
 	configID := r.Context().Value("configID").(int64)
+	receivedAt := time.Now()
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -144,24 +180,39 @@ func (s *webhookServer) handleLivedataUpdate(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	// Heartbeats arrive as updates without items.
+	app.RecordSubscriptionEvent(configID, appmodel.SubscriptionKindData)
 
+	var updates []app.AttributeDataUpdate
 	for _, item := range liveDataUpdate.Items {
-		timestamp, err := time.Parse(time.RFC3339, item.TimeStamp)
+		timestamp, err := broker.ParseTimestamp(item.TimeStamp)
 		if err != nil {
 			log.Warn("webhook", "Invalid timestamp format %v for ID %s: %v", item.TimeStamp, item.DatapointID, err)
 			continue
 		}
 
-		if item.Quality == "good" {
-			app.UpdateDataPointInEliona(app.AttributeDataUpdate{
-				ConfigID:            configID,
-				DatapointProviderID: item.DatapointID,
-				Timestamp:           timestamp,
-				Value:               item.Value,
-			})
-		} else {
-			log.Info("webhook", "Received bad quality data for ID %s: IsProperty=%v, TimeStamp=%v, Quality=%s, Value=%v", item.DatapointID, item.IsProperty, timestamp, item.Quality, item.Value)
+		quality := broker.ParseQuality(item.Quality)
+		if quality != broker.QualityGood {
+			log.Debug("webhook", "Received bad quality data for ID %s: IsProperty=%v, TimeStamp=%v, Quality=%s, Value=%v", item.DatapointID, item.IsProperty, timestamp, item.Quality, item.Value)
 		}
+		updates = append(updates, app.AttributeDataUpdate{
+			ConfigID:            configID,
+			DatapointProviderID: item.DatapointID,
+			Timestamp:           timestamp,
+			ReceivedAt:          receivedAt,
+			Quality:             quality,
+			UnitSymbol:          item.UnitSymbol,
+			Value:               item.Value,
+		})
+	}
+
+	// Writing to Eliona happens in the background, the edge would run into its
+	// timeout waiting for large batches.
+	if !s.ingest.enqueue(configID, updates) {
+		log.Warn("webhook", "Live data queue of config %d is full, rejecting %d updates", configID, len(updates))
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "Too many pending updates", http.StatusServiceUnavailable)
+		return
 	}
 
 	log.Debug("webhook", "Processed live data update. NotificationIdentifier: %s, Id: %s, Tags: %v", liveDataUpdate.NotificationIdentifier, liveDataUpdate.Id, liveDataUpdate.Tags)
@@ -171,6 +222,7 @@ func (s *webhookServer) handleLivedataUpdate(w http.ResponseWriter, r *http.Requ
 
 func (s *webhookServer) handleLiveAlarm(w http.ResponseWriter, r *http.Request) {
 	configID := r.Context().Value("configID").(int64)
+	receivedAt := time.Now()
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -215,27 +267,25 @@ func (s *webhookServer) handleLiveAlarm(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	app.RecordSubscriptionEvent(configID, appmodel.SubscriptionKindAlarm)
 
 	for _, alarm := range liveAlarms {
 		// Timestamps are always in UTC - see docs
-		layout := "02/01/2006 15:04:05"
-		timestamp, err := time.ParseInLocation(layout, alarm.TimeStamp, time.UTC)
+		timestamp, err := broker.ParseTimestamp(alarm.TimeStamp)
 		if err != nil {
 			log.Warn("webhook", "Invalid timestamp format for alarm SessionId %s: %v", alarm.SessionId, err)
 			continue
 		}
 
-		if alarm.Quality != "good" {
-			log.Debug("webhook", "Received alarm with bad quality for SessionId %s: Quality=%s", alarm.SessionId, alarm.Quality)
-			continue
-		}
-
 		alarmUpdate := app.AlarmUpdate{
 			ConfigID:            configID,
 			AlarmID:             alarm.SessionId,
 			DatapointInstanceId: alarm.DataPointInstanceId,
 			Timestamp:           timestamp,
-			Severity:            alarm.Severity,
+			ReceivedAt:          receivedAt,
+			Trigger:             broker.ParseTrigger(alarm.Trigger),
+			Quality:             broker.ParseQuality(alarm.Quality),
+			Severity:            broker.ParseSeverity(alarm.Severity),
 			Active:              alarm.Active,
 			Acked:               alarm.Acked,
 			Closed:              alarm.Closed,
@@ -260,6 +310,47 @@ func (s *webhookServer) handleLiveAlarm(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+// verify checks the secret contained in the request URL against the one of
+// the configuration. Returns the HTTP status to answer with if it does not match.
+func (s *webhookServer) verify(ctx context.Context, configID int64, secret string) int {
+	expected, err := s.webhookSecret(ctx, configID)
+	if errors.Is(err, dbhelper.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	if err != nil {
+		log.Error("webhook", "Getting webhook secret of config %d: %v", configID, err)
+		return http.StatusInternalServerError
+	}
+	if expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		return http.StatusUnauthorized
+	}
+	return http.StatusOK
+}
+
+func (s *webhookServer) reject(configID int64) int {
+	s.rejectedMu.Lock()
+	defer s.rejectedMu.Unlock()
+	s.rejected[configID]++
+	return s.rejected[configID]
+}
+
+// splitSecretFromPath splits "/{secret}/rest-of-path" into the secret and
+// "/rest-of-path". The secret is empty if the path contains none.
+func splitSecretFromPath(path string) (secret string, rest string) {
+	trimmed := strings.TrimPrefix(path, "/")
+	secret, rest, found := strings.Cut(trimmed, "/")
+	if !found {
+		return "", path
+	}
+	return secret, "/" + rest
+}
+
+// redactSecret hides the webhook secret in paths written to the log.
+func redactSecret(path string) string {
+	re := regexp.MustCompile(`^(/\d+/)[^/]+(/[^/]+)$`)
+	return re.ReplaceAllString(path, "${1}***${2}")
+}
+
 func parseConfigIDFromPath(path string) (int64, error) {
 	// Matches "/{configID}/rest-of-path"
 	re := regexp.MustCompile(`^/(\d+)/`)
@@ -277,6 +368,9 @@ func removeConfigIDFromPath(path string) string {
 
 func StartWebhookListener() {
 	server := newWebhookServer()
+	dbhelper.OnConfigRemoved(server.ingest.remove)
+	apiservices.SetLiveDataQueueStats(server.ingest.configStats)
+	go server.ingest.logStats(time.Minute)
 
 	server.mux.HandleFunc("/ontology-version", server.handleOntologyVersion)
 	server.mux.HandleFunc("/ontology-livedata", server.handleLivedataUpdate)
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	dbhelper "open-bos/db/helper"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *webhookServer {
+	server := newWebhookServer()
+	server.webhookSecret = func(ctx context.Context, configID int64) (string, error) {
+		return "s3cret", nil
+	}
+	server.mux.HandleFunc("/ontology-version", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return server
+}
+
+func TestWebhookAcceptsValidSecret(t *testing.T) {
+	server := newTestServer()
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/1/s3cret/ontology-version", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, server.rejected[1])
+}
+
+func TestWebhookRejectsInvalidSecret(t *testing.T) {
+	server := newTestServer()
+
+	for _, path := range []string{"/1/wrong/ontology-version", "/1/ontology-version", "/1//ontology-version"} {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}")))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+	assert.Equal(t, 3, server.rejected[1])
+}
+
+func TestWebhookDoesNotCountUnknownConfigs(t *testing.T) {
+	server := newTestServer()
+	server.webhookSecret = func(ctx context.Context, configID int64) (string, error) {
+		return "", dbhelper.ErrNotFound
+	}
+
+	for configID := range 3 {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/%d/s3cret/ontology-version", configID), strings.NewReader("{}")))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+	assert.Empty(t, server.rejected)
+}
+
+func TestRedactSecret(t *testing.T) {
+	assert.Equal(t, "/1/***/ontology-livedata", redactSecret("/1/s3cret/ontology-livedata"))
+	assert.Equal(t, "/1/ontology-livedata", redactSecret("/1/ontology-livedata"))
+}